@@ -0,0 +1,77 @@
+package timeutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Age computes the calendar difference between two times as a number of
+// whole years, months, and days. Only the dates are considered, and to is
+// converted to from's location before its date is determined.
+//
+// Months are counted by stepping forward from the date of from, clamping to
+// the end of shorter months, and the remaining days are counted from there.
+// So, Jan 31 to Mar 1 of a non-leap year is 1 month (to Feb 28) and 1 day.
+//
+// If to is before from, the components are all negative.
+func Age(from, to time.Time) (years, months, days int) {
+	if to.Before(from) {
+		years, months, days = Age(to, from)
+		return -years, -months, -days
+	}
+	f, t := DateOf(from), DateOf(to.In(from.Location()))
+	n := (t.Year-f.Year)*12 + int(t.Month-f.Month)
+	a := addMonths(f, n)
+	if a.days() > t.days() {
+		n--
+		a = addMonths(f, n)
+	}
+	return n / 12, n % 12, t.days() - a.days()
+}
+
+// FormatAge formats the calendar difference between two times, as computed
+// by [Age], in a form like "2 years, 3 months, 1 day". Components which are
+// zero are omitted. The difference is formatted the same regardless of which
+// time is earlier.
+func FormatAge(from, to time.Time) string {
+	if to.Before(from) {
+		from, to = to, from
+	}
+	years, months, days := Age(from, to)
+	var f []string
+	if years != 0 {
+		f = append(f, plural(years, "year"))
+	}
+	if months != 0 {
+		f = append(f, plural(months, "month"))
+	}
+	if days != 0 || len(f) == 0 {
+		f = append(f, plural(days, "day"))
+	}
+	return strings.Join(f, ", ")
+}
+
+// addMonths adds n months to d, clamping the day to the end of the resulting
+// month if it is shorter.
+func addMonths(d Date, n int) Date {
+	m := int(d.Month) - 1 + n
+	y := d.Year + m/12
+	if m = m % 12; m < 0 {
+		m += 12
+		y--
+	}
+	r := Date{Year: y, Month: time.Month(m + 1), Day: d.Day}
+	if l := daysIn(r.Year, r.Month); r.Day > l {
+		r.Day = l
+	}
+	return r
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	} else {
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+}
@@ -0,0 +1,69 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		From, To time.Time
+		Age      [3]int
+		Expect   string
+	}{
+		{
+			From:   time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+			Expect: "0 days",
+		},
+		{
+			From:   time.Date(2022, 8, 10, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+			Age:    [3]int{2, 3, 4},
+			Expect: "2 years, 3 months, 4 days",
+		},
+		{ // month-end borrow in a non-leap year
+			From:   time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			Age:    [3]int{0, 1, 1},
+			Expect: "1 month, 1 day",
+		},
+		{ // month-end borrow in a leap year
+			From:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			Age:    [3]int{0, 1, 1},
+			Expect: "1 month, 1 day",
+		},
+		{ // not quite a month
+			From:   time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC),
+			Age:    [3]int{0, 0, 30},
+			Expect: "30 days",
+		},
+		{ // leap day birthday
+			From:   time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC),
+			Age:    [3]int{3, 0, 0},
+			Expect: "3 years",
+		},
+		{
+			From:   time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			Age:    [3]int{4, 0, 0},
+			Expect: "4 years",
+		},
+		{ // reversed
+			From:   time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2023, 10, 13, 0, 0, 0, 0, time.UTC),
+			Age:    [3]int{-1, -1, -1},
+			Expect: "1 year, 1 month, 1 day",
+		},
+	}
+	for i, test := range tests {
+		y, m, d := Age(test.From, test.To)
+		assert.Equal(t, test.Age, [3]int{y, m, d}, "#%d", i)
+		assert.Equal(t, test.Expect, FormatAge(test.From, test.To), "#%d", i)
+	}
+}
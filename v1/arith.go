@@ -0,0 +1,96 @@
+package timeutil
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	maxDuration = time.Duration(math.MaxInt64)
+	minDuration = time.Duration(math.MinInt64)
+)
+
+// MulDuration multiplies a duration by n. If the result would overflow, it is
+// saturated to the largest or smallest representable duration, depending on
+// its sign, and ok is false. Saturation is used rather than an error so that
+// the result remains usable as a ceiling in, for example, backoff
+// calculations; callers which need to treat overflow as an error can check ok.
+func MulDuration(d time.Duration, n int64) (v time.Duration, ok bool) {
+	if d == 0 || n == 0 {
+		return 0, true
+	}
+	v = d * time.Duration(n)
+	if (n == -1 && d == minDuration) || v/time.Duration(n) != d {
+		if (d < 0) != (n < 0) {
+			return minDuration, false
+		} else {
+			return maxDuration, false
+		}
+	}
+	return v, true
+}
+
+// DivDuration divides a duration by n. Rather than panicking, division by
+// zero saturates to the largest or smallest representable duration depending
+// on the sign of d, or zero if d is zero. Likewise, dividing the smallest
+// duration by -1 saturates to the largest duration.
+func DivDuration(d time.Duration, n int64) time.Duration {
+	switch {
+	case n == 0 && d > 0:
+		return maxDuration
+	case n == 0 && d < 0:
+		return minDuration
+	case n == 0:
+		return 0
+	case n == -1 && d == minDuration:
+		return maxDuration
+	default:
+		return d / time.Duration(n)
+	}
+}
+
+// DurationSeconds returns a duration as a floating point number of seconds,
+// as expected by metrics systems like Prometheus. A float64 represents every
+// nanosecond exactly only up to 2^53 nanoseconds, or about 104 days; beyond
+// that, the result is the nearest representable value, so longer durations
+// lose nanosecond precision, though never more than about a microsecond.
+func DurationSeconds(d time.Duration) float64 {
+	return d.Seconds()
+}
+
+// DurationFromSeconds converts a floating point number of seconds to a
+// duration, rounded to the nearest nanosecond. Values too large to be
+// represented, including infinities, are saturated to the largest or
+// smallest representable duration, and NaN is converted to zero. The same
+// precision limits as [DurationSeconds] apply.
+func DurationFromSeconds(f float64) time.Duration {
+	ns := math.Round(f * float64(time.Second))
+	switch {
+	case math.IsNaN(ns):
+		return 0
+	case ns >= float64(maxDuration):
+		return maxDuration
+	case ns <= float64(minDuration):
+		return minDuration
+	default:
+		return time.Duration(ns)
+	}
+}
+
+// DurationsClose reports whether a and b differ by no more than tol. The
+// difference is computed without overflow, so it is correct even for the
+// extremes of the duration range. A negative tolerance is never satisfied.
+func DurationsClose(a, b, tol time.Duration) bool {
+	if tol < 0 {
+		return false
+	}
+	// the difference is computed as an unsigned value, which can represent
+	// the distance between any two durations
+	var diff uint64
+	if a >= b {
+		diff = uint64(a) - uint64(b)
+	} else {
+		diff = uint64(b) - uint64(a)
+	}
+	return diff <= uint64(tol)
+}
@@ -0,0 +1,99 @@
+package timeutil
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMulDuration(t *testing.T) {
+	tests := []struct {
+		D      time.Duration
+		N      int64
+		Expect time.Duration
+		OK     bool
+	}{
+		{time.Second, 0, 0, true},
+		{0, math.MaxInt64, 0, true},
+		{time.Second, 3, time.Second * 3, true},
+		{time.Second, -3, -time.Second * 3, true},
+		{-time.Second, -3, time.Second * 3, true},
+		{maxDuration, 1, maxDuration, true},
+		{maxDuration, 2, maxDuration, false},
+		{maxDuration, -2, minDuration, false},
+		{minDuration, -1, maxDuration, false},
+		{-1, math.MinInt64, maxDuration, false},
+		{time.Hour, math.MaxInt64 / 1000, maxDuration, false},
+		{-time.Hour, math.MaxInt64 / 1000, minDuration, false},
+	}
+	for i, test := range tests {
+		v, ok := MulDuration(test.D, test.N)
+		assert.Equal(t, test.Expect, v, "#%d", i)
+		assert.Equal(t, test.OK, ok, "#%d", i)
+	}
+}
+
+func TestDivDuration(t *testing.T) {
+	assert.Equal(t, time.Second, DivDuration(time.Second*3, 3))
+	assert.Equal(t, -time.Second, DivDuration(time.Second*3, -3))
+	assert.Equal(t, maxDuration, DivDuration(time.Second, 0))
+	assert.Equal(t, minDuration, DivDuration(-time.Second, 0))
+	assert.Equal(t, time.Duration(0), DivDuration(0, 0))
+	assert.Equal(t, maxDuration, DivDuration(minDuration, -1))
+}
+
+func TestDurationSeconds(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Seconds  float64
+	}{
+		{0, 0},
+		{time.Nanosecond, 1e-9},
+		{time.Millisecond * 250, 0.25},
+		{time.Microsecond * 1500, 0.0015},
+		{-time.Millisecond * 500, -0.5},
+		{time.Minute * 90, 5400},
+		{day*3 + time.Hour*2 + time.Nanosecond*5, 266400.000000005},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Seconds, DurationSeconds(test.Duration), "#%d", i)
+		assert.Equal(t, test.Duration, DurationFromSeconds(test.Seconds), "#%d", i)
+	}
+	d := day*365 + time.Nanosecond // beyond the exact range of a float64
+	assert.InDelta(t, float64(d), float64(DurationFromSeconds(DurationSeconds(d))), float64(time.Microsecond))
+
+	assert.Equal(t, time.Duration(0), DurationFromSeconds(math.NaN()))
+	assert.Equal(t, maxDuration, DurationFromSeconds(math.Inf(1)))
+	assert.Equal(t, minDuration, DurationFromSeconds(math.Inf(-1)))
+	assert.Equal(t, maxDuration, DurationFromSeconds(1e10))
+	assert.Equal(t, minDuration, DurationFromSeconds(-1e10))
+	assert.Equal(t, time.Nanosecond, DurationFromSeconds(0.6e-9))
+}
+
+func TestDurationsClose(t *testing.T) {
+	tests := []struct {
+		A, B, Tol time.Duration
+		Expect    bool
+	}{
+		{time.Second, time.Second, 0, true},
+		{time.Second, time.Second + time.Millisecond, time.Millisecond, true},
+		{time.Second, time.Second + time.Millisecond + 1, time.Millisecond, false},
+		{time.Second + time.Millisecond, time.Second, time.Millisecond, true},
+		{time.Second - time.Millisecond - 1, time.Second, time.Millisecond, false},
+		{-time.Second, time.Second, time.Second * 2, true},
+		{time.Second, -time.Second, time.Second*2 - 1, false},
+		{-time.Second, -time.Second - time.Millisecond, time.Millisecond, true},
+		{time.Second, time.Second, -1, false},
+		{minDuration, maxDuration, maxDuration, false},
+		{maxDuration, minDuration, maxDuration, false},
+		{minDuration, minDuration + 1, 1, true},
+		{maxDuration, maxDuration - 1, 0, false},
+		{0, minDuration, maxDuration, false},
+		{0, minDuration + 1, maxDuration, true},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, DurationsClose(test.A, test.B, test.Tol), "#%d", i)
+	}
+}
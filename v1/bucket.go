@@ -0,0 +1,39 @@
+package timeutil
+
+import (
+	"time"
+)
+
+// DefaultLatencyBuckets are bucket bounds suitable for labeling request
+// latencies with [DurationBucket].
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	time.Millisecond * 10,
+	time.Millisecond * 100,
+	time.Second,
+	time.Second * 10,
+}
+
+// DurationBucket returns a label for the bucket that a duration falls into,
+// given ascending bucket bounds. Buckets include their lower bound and
+// exclude their upper bound. Durations below the first bound are labeled
+// like "<1ms", durations at or above the last bound are labeled like ">=10s",
+// and durations in between are labeled like "1ms-10ms". Bounds are formatted
+// with [FormatDuration].
+//
+// If no bounds are provided, every duration falls into a single unlabeled
+// bucket and the empty string is returned.
+func DurationBucket(d time.Duration, bounds []time.Duration) string {
+	if len(bounds) == 0 {
+		return ""
+	}
+	if d < bounds[0] {
+		return "<" + FormatDuration(bounds[0])
+	}
+	for i := 1; i < len(bounds); i++ {
+		if d < bounds[i] {
+			return FormatDuration(bounds[i-1]) + "-" + FormatDuration(bounds[i])
+		}
+	}
+	return ">=" + FormatDuration(bounds[len(bounds)-1])
+}
@@ -0,0 +1,32 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationBucket(t *testing.T) {
+	tests := []struct {
+		D      time.Duration
+		Expect string
+	}{
+		{0, "<1ms"},
+		{time.Microsecond * 999, "<1ms"},
+		{time.Millisecond, "1ms-10ms"},
+		{time.Millisecond * 9, "1ms-10ms"},
+		{time.Millisecond * 10, "10ms-100ms"},
+		{time.Millisecond * 100, "100ms-1s"},
+		{time.Millisecond * 1500, "1s-10s"},
+		{time.Second*10 - 1, "1s-10s"},
+		{time.Second * 10, ">=10s"},
+		{time.Hour, ">=10s"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, DurationBucket(test.D, DefaultLatencyBuckets), "#%d", i)
+	}
+	assert.Equal(t, "<1m", DurationBucket(time.Second, []time.Duration{time.Minute}))
+	assert.Equal(t, ">=1m", DurationBucket(time.Minute, []time.Duration{time.Minute}))
+	assert.Equal(t, "", DurationBucket(time.Minute, nil))
+}
@@ -0,0 +1,74 @@
+package timeutil
+
+import (
+	"time"
+)
+
+// IsBusinessDay reports whether t falls on a day that is not a Saturday, a
+// Sunday, or one of the provided holidays. The holidays set may be nil.
+func IsBusinessDay(t time.Time, holidays map[Date]bool) bool {
+	switch t.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !holidays[DateOf(t)]
+}
+
+// NextBusinessDay returns the next business day after t, at the same
+// wall-clock time as t. Weekends and the provided holidays are skipped.
+func NextBusinessDay(t time.Time, holidays map[Date]bool) time.Time {
+	return AddBusinessDays(t, 1, holidays)
+}
+
+// AddBusinessDays skips forward n business days from t, or backward if n is
+// negative, and returns the resulting day at the same wall-clock time as t.
+// Days are stepped on the calendar, so the result is correct across daylight
+// saving transitions. If n is zero, t is returned unchanged.
+func AddBusinessDays(t time.Time, n int, holidays map[Date]bool) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if IsBusinessDay(t, holidays) {
+			n--
+		}
+	}
+	return t
+}
+
+// BusinessDaysBetween returns the number of business days after the day of
+// from, up to and including the day of to, so that it counts the same days
+// as [AddBusinessDays] and BusinessDaysBetween(t, AddBusinessDays(t, n, h), h)
+// is n for any n that is not negative. Only the dates are considered, and to
+// is converted to from's location before its date is determined. Weekends and
+// the provided holidays are not counted. If to is before from, the result is
+// negative.
+func BusinessDaysBetween(from, to time.Time, holidays map[Date]bool) int {
+	f, t := DateOf(from), DateOf(to.In(from.Location()))
+	if t.days() < f.days() {
+		return -businessDaysBetween(t, f, holidays)
+	}
+	return businessDaysBetween(f, t, holidays)
+}
+
+// businessDaysBetween counts the business days after f, up to and including
+// t, which must not be before f. Whole weeks are counted arithmetically, so
+// the cost depends on the number of holidays rather than the number of days.
+func businessDaysBetween(f, t Date, holidays map[Date]bool) int {
+	lo, hi := f.days(), t.days()
+	n := (hi - lo) / 7 * 5
+	for d := f.AddDays((hi - lo) / 7 * 7); d.days() < hi; {
+		d = d.AddDays(1)
+		if IsBusinessDay(d.In(time.UTC), nil) {
+			n++
+		}
+	}
+	for h, ok := range holidays {
+		if ok && lo < h.days() && h.days() <= hi && IsBusinessDay(h.In(time.UTC), nil) {
+			n--
+		}
+	}
+	return n
+}
@@ -0,0 +1,113 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessDays(t *testing.T) {
+	fri := time.Date(2024, 11, 15, 9, 30, 0, 0, time.UTC)
+	holidays := map[Date]bool{
+		{2024, 11, 19}: true,
+	}
+	tests := []struct {
+		Ref      time.Time
+		N        int
+		Holidays map[Date]bool
+		Expect   time.Time
+	}{
+		{
+			Ref:    fri,
+			N:      0,
+			Expect: fri,
+		},
+		{
+			Ref:    fri,
+			N:      1,
+			Expect: time.Date(2024, 11, 18, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			Ref:    fri,
+			N:      3,
+			Expect: time.Date(2024, 11, 20, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			Ref:      fri,
+			N:        3,
+			Holidays: holidays,
+			Expect:   time.Date(2024, 11, 21, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			Ref:      time.Date(2024, 11, 21, 9, 30, 0, 0, time.UTC),
+			N:        -3,
+			Holidays: holidays,
+			Expect:   fri,
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, AddBusinessDays(test.Ref, test.N, test.Holidays), "#%d", i)
+	}
+
+	assert.Equal(t, time.Date(2024, 11, 18, 9, 30, 0, 0, time.UTC), NextBusinessDay(fri, nil))
+	assert.Equal(t, time.Date(2024, 11, 20, 9, 30, 0, 0, time.UTC), NextBusinessDay(time.Date(2024, 11, 18, 9, 30, 0, 0, time.UTC), holidays))
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	date := func(d int) time.Time {
+		return time.Date(2024, 11, d, 9, 30, 0, 0, time.UTC)
+	}
+	holidays := map[Date]bool{
+		{2024, 11, 19}: true,
+		{2024, 11, 23}: true, // a Saturday
+		{2024, 11, 20}: false,
+	}
+	tests := []struct {
+		From, To time.Time
+		Holidays map[Date]bool
+		Expect   int
+	}{
+		{From: date(15), To: date(15), Expect: 0},
+		{From: date(15), To: date(15).Add(time.Hour * 12), Expect: 0},  // same day
+		{From: date(15), To: date(18), Expect: 1},                      // Friday to Monday
+		{From: date(16), To: date(18), Expect: 1},                      // Saturday to Monday
+		{From: date(15), To: date(17), Expect: 0},                      // Friday to Sunday
+		{From: date(11), To: date(22), Expect: 9},                      // Monday to the next Friday
+		{From: date(1), To: date(29), Expect: 20},                      // four weeks
+		{From: date(15), To: date(21), Holidays: holidays, Expect: 3},  // Friday to Thursday, with a holiday
+		{From: date(15), To: date(25), Holidays: holidays, Expect: 5},  // a holiday on a weekend is not double counted
+		{From: date(18), To: date(15), Expect: -1},                     // backward
+		{From: date(21), To: date(15), Holidays: holidays, Expect: -3}, // backward, with a holiday
+		{From: date(19), To: date(19), Holidays: holidays, Expect: 0},  // on a holiday
+		{From: date(18), To: date(19), Holidays: holidays, Expect: 0},  // to a holiday
+		{From: date(19), To: date(20), Holidays: holidays, Expect: 1},  // from a holiday
+		{From: date(1), To: date(1).AddDate(1, 0, 0), Expect: 260},     // a whole year, ending on a Saturday
+		{From: date(1), To: date(1).AddDate(0, 0, 71), Expect: 50},     // weeks and a remainder
+		{From: date(1), To: date(1).AddDate(0, 0, -71), Expect: -51},   // backward, weeks and a remainder
+		{From: date(1), To: date(1).AddDate(0, 0, 3), Expect: 1},       // Friday to Monday
+		{From: date(1), To: date(1).AddDate(0, 0, 6), Expect: 4},       // Friday to Thursday
+		{From: date(14), To: date(14).AddDate(0, 0, 14), Expect: 10},   // Thursday, two weeks
+		{From: date(14), To: date(14).AddDate(0, 0, 15), Expect: 11},   // Thursday to Friday
+		{From: date(14), To: date(14).AddDate(0, 0, 16), Expect: 11},   // Thursday to Saturday
+		{From: date(14), To: date(14).AddDate(0, 0, 17), Expect: 11},   // Thursday to Sunday
+		{From: date(14), To: date(14).AddDate(0, 0, 18), Expect: 12},   // Thursday to Monday
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, BusinessDaysBetween(test.From, test.To, test.Holidays), "#%d", i)
+	}
+
+	// the inverse of AddBusinessDays
+	for _, from := range []time.Time{date(14), date(16), date(19)} {
+		for n := 0; n < 30; n++ {
+			to := AddBusinessDays(from, n, holidays)
+			assert.Equal(t, n, BusinessDaysBetween(from, to, holidays), "%v +%d", from, n)
+		}
+	}
+
+	// dates are determined in from's location
+	nyc := mustLoadLocation(t, "America/New_York")
+	from := time.Date(2024, 11, 15, 20, 0, 0, 0, nyc)                          // Friday evening
+	assert.Equal(t, 0, BusinessDaysBetween(from, from.Add(time.Hour*2), nil))  // Saturday in UTC
+	assert.Equal(t, 1, BusinessDaysBetween(from, from.Add(time.Hour*60), nil)) // Monday
+}
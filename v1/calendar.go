@@ -0,0 +1,385 @@
+package timeutil
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TruncateTo returns t rounded down to a multiple of unit. Units smaller
+// than a day are handled like time.Truncate, in absolute time.
+//
+// Units of one day or longer are handled using calendar arithmetic in the
+// provided location, or t's location if it is nil, so that the result is
+// always midnight in that location regardless of its offset from UTC or any
+// daylight saving transitions. A unit of one week truncates to the start of
+// the week, beginning on [DefaultWeekStart]. Other multi-day units truncate
+// to multiples of that many days counted from the Unix epoch.
+//
+// The result is expressed in the provided location. If unit is not positive,
+// t is returned unchanged.
+func TruncateTo(t time.Time, unit time.Duration, loc *time.Location) time.Time {
+	if unit <= 0 {
+		return t
+	}
+	if unit%day != 0 {
+		return t.Truncate(unit)
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	d := DateOf(t)
+	switch unit {
+	case day:
+	case week:
+		d = d.AddDays(-mod(int(t.Weekday()-DefaultWeekStart), 7))
+	default:
+		d = d.AddDays(-mod(d.days(), int(unit/day)))
+	}
+	return d.In(t.Location())
+}
+
+// RoundTo returns t rounded to the nearest multiple of unit, with halfway
+// values rounded up. Boundaries are determined like [TruncateTo], so units of
+// one day or longer are handled using calendar arithmetic in the provided
+// location and the result is midnight in that location.
+func RoundTo(t time.Time, unit time.Duration, loc *time.Location) time.Time {
+	if unit <= 0 {
+		return t
+	}
+	if unit%day != 0 {
+		return t.Round(unit)
+	}
+	lo := TruncateTo(t, unit, loc)
+	hi := lo.AddDate(0, 0, int(unit/day))
+	if t.Sub(lo) < hi.Sub(t) {
+		return lo
+	} else {
+		return hi
+	}
+}
+
+// FloorTo returns t rounded down to the nearest interval boundary, where
+// boundaries are anchored at midnight in the provided location, or t's
+// location if it is nil, rather than at the Unix epoch. For example, with
+// an interval of 5 minutes, boundaries fall at :00, :05, and so on, even in
+// locations whose offset from UTC is not a whole number of hours.
+//
+// Intervals shorter than a day are stepped in absolute time from midnight,
+// so on a day with a daylight saving transition the boundaries after the
+// transition are shifted with it, and the last interval of a day is cut
+// short at the following midnight if it does not divide the day evenly.
+// Intervals of a whole number of days are handled like [TruncateTo].
+//
+// The result is expressed in the provided location. If interval is not
+// positive, t is returned unchanged.
+func FloorTo(t time.Time, interval time.Duration, loc *time.Location) time.Time {
+	if interval <= 0 {
+		return t
+	}
+	if interval%day == 0 {
+		return TruncateTo(t, interval, loc)
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	base := StartOfDay(t)
+	return base.Add(t.Sub(base) / interval * interval)
+}
+
+// CeilTo returns t rounded up to the nearest interval boundary, as determined
+// by [FloorTo]. A time which is already on a boundary is returned unchanged,
+// in the provided location.
+func CeilTo(t time.Time, interval time.Duration, loc *time.Location) time.Time {
+	if interval <= 0 {
+		return t
+	}
+	lo := FloorTo(t, interval, loc)
+	if lo.Equal(t) {
+		return lo
+	}
+	if interval%day == 0 {
+		return lo.AddDate(0, 0, int(interval/day))
+	}
+	hi := lo.Add(interval)
+	if next := StartOfDay(lo).AddDate(0, 0, 1); hi.After(next) {
+		return next
+	}
+	return hi
+}
+
+// StartOfDay returns midnight at the beginning of t's day, in t's location.
+func StartOfDay(t time.Time) time.Time {
+	return TruncateTo(t, day, nil)
+}
+
+// EndOfDay returns the last instant of t's day, in t's location.
+func EndOfDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// StartOfWeek returns midnight at the beginning of t's week, in t's location,
+// where weeks begin on the provided weekday.
+func StartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	return StartOfDay(t).AddDate(0, 0, -mod(int(t.Weekday()-weekStart), 7))
+}
+
+// EndOfWeek returns the last instant of t's week, in t's location, where
+// weeks begin on the provided weekday.
+func EndOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	return StartOfWeek(t, weekStart).AddDate(0, 0, 7).Add(-time.Nanosecond)
+}
+
+// DefaultWeekStart is the day on which weeks begin wherever a week start is
+// not provided explicitly: in [StartOfWeekDefault] and [EndOfWeekDefault],
+// when truncating to weeks with [TruncateTo], and when evaluating week
+// expressions without [ExprOptions.WeekStart]. It is Monday by default, as
+// in ISO 8601, but may be set to time.Sunday, for example, for US users.
+// It is global state: it should be set once at startup, before any times are
+// computed, and it applies to every caller in the program, including other
+// packages. Code which may run alongside code that expects a different week
+// start should pass one explicitly, to [StartOfWeek] or via [ExprOptions],
+// instead. [ISOWeek] and [Calendar] are not affected by it.
+var DefaultWeekStart = time.Monday
+
+// StartOfWeekDefault is a convenience interface to [StartOfWeek] which
+// provides [DefaultWeekStart] as the day on which weeks begin.
+func StartOfWeekDefault(t time.Time) time.Time {
+	return StartOfWeek(t, DefaultWeekStart)
+}
+
+// EndOfWeekDefault is a convenience interface to [EndOfWeek] which provides
+// [DefaultWeekStart] as the day on which weeks begin.
+func EndOfWeekDefault(t time.Time) time.Time {
+	return EndOfWeek(t, DefaultWeekStart)
+}
+
+// StartOfMonth returns midnight on the first day of t's month, in t's
+// location.
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfMonth returns the last instant of t's month, in t's location.
+func EndOfMonth(t time.Time) time.Time {
+	return StartOfMonth(t).AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+// StartOfYear returns midnight on the first day of t's year, in t's location.
+func StartOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfYear returns the last instant of t's year, in t's location.
+func EndOfYear(t time.Time) time.Time {
+	return StartOfYear(t).AddDate(1, 0, 0).Add(-time.Nanosecond)
+}
+
+// ISOWeek returns the ISO 8601 year and week number in which t occurs, in t's
+// location. Weeks begin on Monday and week 1 is the week containing the
+// year's first Thursday, so the ISO year may differ from t's calendar year
+// near its beginning and end.
+func ISOWeek(t time.Time) (year, week int) {
+	return t.ISOWeek()
+}
+
+// DayOfYear returns the day of the year on which t occurs, in t's location,
+// from 1 through 365, or 366 in leap years.
+func DayOfYear(t time.Time) int {
+	return t.YearDay()
+}
+
+// WeeksInYear returns the number of weeks in an ISO 8601 year, which is 53
+// for years which begin or, in leap years, end on a Thursday, and 52
+// otherwise.
+func WeeksInYear(year int) int {
+	_, w := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return w
+}
+
+// Calendar bundles the settings which calendar operations depend on, a
+// location and the day on which weeks begin, so that they can be applied
+// consistently without being passed to every function. Each method converts
+// times to the calendar's location and then delegates to the standalone
+// function of the same name. A nil location is treated as UTC. Note that the
+// zero weekday is Sunday, so weeks in the zero calendar begin on Sunday.
+type Calendar struct {
+	Location  *time.Location
+	WeekStart time.Weekday
+}
+
+// NewCalendar creates a calendar in the provided location with weeks that
+// begin on the provided weekday.
+func NewCalendar(loc *time.Location, weekStart time.Weekday) Calendar {
+	return Calendar{
+		Location:  loc,
+		WeekStart: weekStart,
+	}
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location != nil {
+		return c.Location
+	} else {
+		return time.UTC
+	}
+}
+
+// In converts t to the calendar's location.
+func (c Calendar) In(t time.Time) time.Time {
+	return t.In(c.location())
+}
+
+// Now returns the current time, as reported by [Now], in the calendar's
+// location.
+func (c Calendar) Now() time.Time {
+	return c.In(Now())
+}
+
+// Today returns midnight at the beginning of the current day in the
+// calendar's location.
+func (c Calendar) Today() time.Time {
+	return StartOfDay(c.Now())
+}
+
+// StartOfDay returns midnight at the beginning of t's day.
+func (c Calendar) StartOfDay(t time.Time) time.Time {
+	return StartOfDay(c.In(t))
+}
+
+// EndOfDay returns the last instant of t's day.
+func (c Calendar) EndOfDay(t time.Time) time.Time {
+	return EndOfDay(c.In(t))
+}
+
+// StartOfWeek returns midnight at the beginning of t's week.
+func (c Calendar) StartOfWeek(t time.Time) time.Time {
+	return StartOfWeek(c.In(t), c.WeekStart)
+}
+
+// EndOfWeek returns the last instant of t's week.
+func (c Calendar) EndOfWeek(t time.Time) time.Time {
+	return EndOfWeek(c.In(t), c.WeekStart)
+}
+
+// StartOfMonth returns midnight on the first day of t's month.
+func (c Calendar) StartOfMonth(t time.Time) time.Time {
+	return StartOfMonth(c.In(t))
+}
+
+// EndOfMonth returns the last instant of t's month.
+func (c Calendar) EndOfMonth(t time.Time) time.Time {
+	return EndOfMonth(c.In(t))
+}
+
+// StartOfYear returns midnight on the first day of t's year.
+func (c Calendar) StartOfYear(t time.Time) time.Time {
+	return StartOfYear(c.In(t))
+}
+
+// EndOfYear returns the last instant of t's year.
+func (c Calendar) EndOfYear(t time.Time) time.Time {
+	return EndOfYear(c.In(t))
+}
+
+// Options returns expression options which use the calendar's week start.
+func (c Calendar) Options() ExprOptions {
+	ws := c.WeekStart
+	return ExprOptions{WeekStart: &ws}
+}
+
+// ParseExpr parses a time expression like [ParseExprRefWith], relative to the
+// current time in the calendar's location and using its week start.
+func (c Calendar) ParseExpr(s string) (time.Time, error) {
+	return ParseExprRefWith(s, c.Now(), c.Options())
+}
+
+// ParseRangeExpr parses a range expression like [ParseRangeExprRefWith],
+// relative to the current time in the calendar's location and using its
+// week start.
+func (c Calendar) ParseRangeExpr(s string) (TimeRange, error) {
+	return ParseRangeExprRefWith(s, c.Now(), c.Options())
+}
+
+// calendarUnits are the units of a calendar expression which are applied with
+// time.AddDate, mapped to the number of each of years, months, and days they
+// represent.
+var calendarUnits = map[string][3]int{
+	"y":  {1, 0, 0},
+	"mo": {0, 1, 0},
+	"w":  {0, 0, 7},
+	"d":  {0, 0, 1},
+}
+
+// AddExpr adds a calendar expression to a time. A calendar expression is like
+// a duration accepted by [ParseDuration], except that it also supports the
+// units "y" and "mo", for years and months, like "1y6mo" or "-1mo2d".
+//
+// Unlike durations, which always have a fixed length, the units "y", "mo",
+// "w", and "d" are calendar units, applied together with time.AddDate in t's
+// location, so "1y" from February 29th is March 1st of the following year,
+// and "1d" across a daylight saving transition is the same wall-clock time on
+// the next day even though it is 23 or 25 hours later. Calendar units must be
+// whole numbers. The remaining units are fixed durations, which are added
+// afterward in absolute time. A leading sign applies to every component.
+func AddExpr(t time.Time, expr string) (time.Time, error) {
+	s := expr
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return time.Time{}, errors.New("time: invalid calendar expression " + quote(expr))
+	}
+	var date [3]int
+	var fixed strings.Builder
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] == '.' || '0' <= s[i] && s[i] <= '9') {
+			i++
+		}
+		n := s[:i]
+		s = s[i:]
+		i = 0
+		for i < len(s) && s[i] != '.' && (s[i] < '0' || s[i] > '9') {
+			i++
+		}
+		u := s[:i]
+		s = s[i:]
+		if n == "" || u == "" {
+			return time.Time{}, errors.New("time: invalid calendar expression " + quote(expr))
+		}
+		c, ok := calendarUnits[u]
+		if !ok {
+			fixed.WriteString(n + u)
+			continue
+		}
+		v, err := strconv.Atoi(n)
+		if err != nil {
+			return time.Time{}, errors.New("time: calendar unit " + quote(u) + " requires a whole number in " + quote(expr))
+		}
+		for j := range date {
+			date[j] += c[j] * v
+		}
+	}
+	var d time.Duration
+	if fixed.Len() > 0 {
+		var err error
+		d, err = ParseDuration(fixed.String())
+		if errors.Is(err, ErrDurationOverflow) {
+			return time.Time{}, overflowError(expr)
+		} else if err != nil {
+			return time.Time{}, errors.New("time: invalid calendar expression " + quote(expr))
+		}
+	}
+	if neg {
+		for j := range date {
+			date[j] = -date[j]
+		}
+		d = -d
+	}
+	return t.AddDate(date[0], date[1], date[2]).Add(d), nil
+}
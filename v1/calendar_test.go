@@ -0,0 +1,374 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartEndOf(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	tests := []struct {
+		Value, Expect time.Time
+	}{
+		{StartOfDay(ref), time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)},
+		{EndOfDay(ref), time.Date(2024, 11, 14, 23, 59, 59, 999999999, time.UTC)},
+		{StartOfWeek(ref, time.Monday), time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC)},
+		{StartOfWeek(ref, time.Sunday), time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC)},
+		{StartOfWeek(ref, time.Thursday), time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)},
+		{StartOfWeek(ref, time.Friday), time.Date(2024, 11, 8, 0, 0, 0, 0, time.UTC)},
+		{EndOfWeek(ref, time.Monday), time.Date(2024, 11, 17, 23, 59, 59, 999999999, time.UTC)},
+		{StartOfMonth(ref), time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{EndOfMonth(ref), time.Date(2024, 11, 30, 23, 59, 59, 999999999, time.UTC)},
+		{EndOfMonth(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)), time.Date(2024, 2, 29, 23, 59, 59, 999999999, time.UTC)},
+		{StartOfYear(ref), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{EndOfYear(ref), time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+		// the spring-forward day is only 23 hours long
+		{StartOfDay(time.Date(2024, 3, 10, 12, 0, 0, 0, nyc)), time.Date(2024, 3, 10, 0, 0, 0, 0, nyc)},
+		{EndOfDay(time.Date(2024, 3, 10, 12, 0, 0, 0, nyc)), time.Date(2024, 3, 10, 23, 59, 59, 999999999, nyc)},
+	}
+	for i, test := range tests {
+		assert.True(t, test.Expect.Equal(test.Value), "#%d: expected %v, got %v", i, test.Expect, test.Value)
+	}
+}
+
+func TestTruncateRoundTo(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	tests := []struct {
+		Value, Expect time.Time
+	}{
+		// sub-day units in absolute time
+		{TruncateTo(time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), time.Hour, nil), time.Date(2024, 11, 14, 18, 0, 0, 0, time.UTC)},
+		{RoundTo(time.Date(2024, 11, 14, 18, 30, 0, 0, time.UTC), time.Hour, nil), time.Date(2024, 11, 14, 19, 0, 0, 0, time.UTC)},
+		// days in another location
+		{TruncateTo(time.Date(2024, 11, 14, 2, 0, 0, 0, time.UTC), day, nyc), time.Date(2024, 11, 13, 0, 0, 0, 0, nyc)},
+		{TruncateTo(time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), day, nil), time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)},
+		// spring forward, a 23 hour day
+		{TruncateTo(time.Date(2024, 3, 10, 23, 0, 0, 0, nyc), day, nyc), time.Date(2024, 3, 10, 0, 0, 0, 0, nyc)},
+		{RoundTo(time.Date(2024, 3, 10, 11, 45, 0, 0, nyc), day, nyc), time.Date(2024, 3, 10, 0, 0, 0, 0, nyc)},
+		{RoundTo(time.Date(2024, 3, 10, 12, 30, 0, 0, nyc), day, nyc), time.Date(2024, 3, 11, 0, 0, 0, 0, nyc)},
+		// fall back, a 25 hour day
+		{TruncateTo(time.Date(2024, 11, 3, 23, 30, 0, 0, nyc), day, nyc), time.Date(2024, 11, 3, 0, 0, 0, 0, nyc)},
+		{RoundTo(time.Date(2024, 11, 3, 11, 15, 0, 0, nyc), day, nyc), time.Date(2024, 11, 3, 0, 0, 0, 0, nyc)}, // the midpoint is 11:30 local
+		{RoundTo(time.Date(2024, 11, 3, 12, 30, 0, 0, nyc), day, nyc), time.Date(2024, 11, 4, 0, 0, 0, 0, nyc)},
+		// weeks begin on monday
+		{TruncateTo(time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), week, nil), time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC)},
+		{TruncateTo(time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC), week, nil), time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC)},
+		{RoundTo(time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), week, nil), time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC)},
+		// non-positive units
+		{TruncateTo(time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), 0, nil), time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)},
+	}
+	for i, test := range tests {
+		assert.True(t, test.Expect.Equal(test.Value), "#%d: expected %v, got %v", i, test.Expect, test.Value)
+		assert.Equal(t, test.Expect.Location(), test.Value.Location(), "#%d", i)
+	}
+}
+
+func TestISOWeek(t *testing.T) {
+	tests := []struct {
+		Time       time.Time
+		Year, Week int
+	}{
+		{time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), 2024, 46},
+		{time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), 2020, 53},
+		{time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), 2020, 53},
+		{time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), 2021, 1},
+		{time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), 2025, 1},
+		{time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), 2026, 53},
+	}
+	for i, test := range tests {
+		y, w := ISOWeek(test.Time)
+		assert.Equal(t, test.Year, y, "#%d", i)
+		assert.Equal(t, test.Week, w, "#%d", i)
+	}
+}
+
+func TestWeeksInYear(t *testing.T) {
+	tests := []struct {
+		Year, Expect int
+	}{
+		{2015, 53},
+		{2019, 52},
+		{2020, 53}, // a leap year ending on a Thursday
+		{2021, 52},
+		{2024, 52},
+		{2026, 53},
+		{2032, 53},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, WeeksInYear(test.Year), "#%d", i)
+	}
+}
+
+func TestDayOfYear(t *testing.T) {
+	tests := []struct {
+		Time   time.Time
+		Expect int
+	}{
+		{time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), 1},
+		{time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), 60},
+		{time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), 365},
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), 60},
+		{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 61},
+		{time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC), 366},
+		{time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC).In(time.FixedZone("", -5*3600)), 366}, // Dec 31 in the time's location
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, DayOfYear(test.Time), "#%d", i)
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ref := time.Date(2024, 11, 14, 3, 17, 0, 0, time.UTC) // a Wednesday evening in New York
+	defer func(f func() time.Time) { Now = f }(Now)
+	Now = func() time.Time { return ref }
+
+	c := NewCalendar(nyc, time.Sunday)
+	local := ref.In(nyc)
+	assert.Equal(t, local, c.Now())
+	assert.Equal(t, StartOfDay(local), c.Today())
+	assert.Equal(t, time.Date(2024, 11, 13, 0, 0, 0, 0, nyc), c.Today())
+	assert.Equal(t, StartOfDay(local), c.StartOfDay(ref))
+	assert.Equal(t, EndOfDay(local), c.EndOfDay(ref))
+	assert.Equal(t, StartOfWeek(local, time.Sunday), c.StartOfWeek(ref))
+	assert.Equal(t, time.Date(2024, 11, 10, 0, 0, 0, 0, nyc), c.StartOfWeek(ref))
+	assert.Equal(t, EndOfWeek(local, time.Sunday), c.EndOfWeek(ref))
+	assert.Equal(t, StartOfMonth(local), c.StartOfMonth(ref))
+	assert.Equal(t, EndOfMonth(local), c.EndOfMonth(ref))
+	assert.Equal(t, StartOfYear(local), c.StartOfYear(ref))
+	assert.Equal(t, EndOfYear(local), c.EndOfYear(ref))
+
+	sunday := time.Sunday
+	opts := ExprOptions{WeekStart: &sunday}
+	for _, e := range []string{"today", "eow", "monday 9am", "-1h"} {
+		expect, err := ParseExprRefWith(e, local, opts)
+		if assert.NoError(t, err, e) {
+			v, err := c.ParseExpr(e)
+			if assert.NoError(t, err, e) {
+				assert.Equal(t, expect, v, e)
+			}
+		}
+	}
+	expect, err := ParseRangeExprRefWith("this week", local, opts)
+	if assert.NoError(t, err) {
+		v, err := c.ParseRangeExpr("this week")
+		if assert.NoError(t, err) {
+			assert.Equal(t, expect, v)
+		}
+	}
+
+	var zero Calendar
+	assert.Equal(t, time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC), zero.Today())
+}
+
+func TestFloorCeilTo(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ist := time.FixedZone("IST", 5*3600+30*60)
+	utc := func(h, m, s int) time.Time {
+		return time.Date(2024, 11, 14, h, m, s, 0, time.UTC)
+	}
+	tests := []struct {
+		Time        time.Time
+		Interval    time.Duration
+		Loc         *time.Location
+		Floor, Ceil time.Time
+	}{
+		{utc(18, 17, 0), time.Minute * 5, nil, utc(18, 15, 0), utc(18, 20, 0)},
+		{utc(18, 17, 0), time.Minute * 15, nil, utc(18, 15, 0), utc(18, 30, 0)},
+		{utc(18, 17, 0), time.Hour, nil, utc(18, 0, 0), utc(19, 0, 0)},
+		{utc(18, 15, 0), time.Minute * 15, nil, utc(18, 15, 0), utc(18, 15, 0)}, // on a boundary
+		{utc(18, 15, 1), time.Minute * 15, nil, utc(18, 15, 0), utc(18, 30, 0)},
+		{ // anchored at midnight in a half-hour offset location, not the epoch
+			Time:     time.Date(2024, 11, 14, 18, 17, 0, 0, ist),
+			Interval: time.Hour,
+			Floor:    time.Date(2024, 11, 14, 18, 0, 0, 0, ist),
+			Ceil:     time.Date(2024, 11, 14, 19, 0, 0, 0, ist),
+		},
+		{ // converted to the provided location
+			Time:     utc(12, 47, 0),
+			Interval: time.Hour,
+			Loc:      ist,
+			Floor:    time.Date(2024, 11, 14, 18, 0, 0, 0, ist),
+			Ceil:     time.Date(2024, 11, 14, 19, 0, 0, 0, ist),
+		},
+		{ // the last interval of the day is cut short at midnight
+			Time:     utc(23, 58, 0),
+			Interval: time.Minute * 7,
+			Floor:    utc(23, 55, 0),
+			Ceil:     time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{ // days are calendar days across the fall-back transition
+			Time:     time.Date(2024, 11, 3, 12, 0, 0, 0, nyc),
+			Interval: day,
+			Floor:    time.Date(2024, 11, 3, 0, 0, 0, 0, nyc),
+			Ceil:     time.Date(2024, 11, 4, 0, 0, 0, 0, nyc),
+		},
+		{ // hours are stepped from midnight across the spring-forward transition
+			Time:     time.Date(2024, 3, 10, 3, 30, 0, 0, nyc),
+			Interval: time.Hour,
+			Floor:    time.Date(2024, 3, 10, 3, 0, 0, 0, nyc),
+			Ceil:     time.Date(2024, 3, 10, 4, 0, 0, 0, nyc),
+		},
+		{utc(18, 17, 0), 0, nil, utc(18, 17, 0), utc(18, 17, 0)},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Floor, FloorTo(test.Time, test.Interval, test.Loc), "#%d", i)
+		assert.Equal(t, test.Ceil, CeilTo(test.Time, test.Interval, test.Loc), "#%d", i)
+	}
+}
+
+// TestCalendarDayLength codifies that the calendar helpers step days on the
+// calendar rather than by a fixed 86400 seconds, so that on a day with a
+// daylight saving transition, which is 23 or 25 hours long, every hour of the
+// day still resolves to the same midnight boundaries. Leap seconds are not
+// modeled by the time package, so every minute is 60 seconds long.
+func TestCalendarDayLength(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	tests := []struct {
+		Day    time.Time
+		Length time.Duration
+	}{
+		{time.Date(2024, 3, 10, 0, 0, 0, 0, nyc), time.Hour * 23},  // spring forward
+		{time.Date(2024, 11, 3, 0, 0, 0, 0, nyc), time.Hour * 25},  // fall back
+		{time.Date(2024, 11, 14, 0, 0, 0, 0, nyc), time.Hour * 24}, // an ordinary day
+		{time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC), day},       // ends with a leap second
+	}
+	for i, test := range tests {
+		next := time.Date(test.Day.Year(), test.Day.Month(), test.Day.Day()+1, 0, 0, 0, 0, test.Day.Location())
+		assert.Equal(t, test.Length, next.Sub(test.Day), "#%d", i)
+		for at := test.Day; at.Before(next); at = at.Add(time.Minute * 30) {
+			assert.Equal(t, test.Day, StartOfDay(at), "#%d: %v", i, at)
+			assert.Equal(t, next.Add(-time.Nanosecond), EndOfDay(at), "#%d: %v", i, at)
+			assert.Equal(t, test.Day, TruncateTo(at, day, nil), "#%d: %v", i, at)
+			assert.Equal(t, test.Day, FloorTo(at, day, nil), "#%d: %v", i, at)
+			assert.Equal(t, 0, StartOfWeek(at, time.Monday).Hour(), "#%d: %v", i, at)
+			assert.Equal(t, time.Monday, StartOfWeek(at, time.Monday).Weekday(), "#%d: %v", i, at)
+			assert.Equal(t, 59, EndOfWeek(at, time.Monday).Second(), "#%d: %v", i, at)
+			assert.Equal(t, time.Sunday, EndOfWeek(at, time.Monday).Weekday(), "#%d: %v", i, at)
+			assert.Equal(t, 1, StartOfMonth(at).Day(), "#%d: %v", i, at)
+			assert.Equal(t, 0, StartOfMonth(at).Hour(), "#%d: %v", i, at)
+		}
+		if test.Length != day {
+			// a fixed 24 hours from midnight lands on the wrong wall-clock time
+			assert.NotEqual(t, 0, test.Day.Add(day).Hour(), "#%d", i)
+			assert.Equal(t, next, CeilTo(test.Day.Add(time.Hour*12), day, nil), "#%d", i)
+			assert.Equal(t, next, RoundTo(test.Day.Add(test.Length/2+time.Minute), day, nil), "#%d", i)
+			assert.Equal(t, test.Day, RoundTo(test.Day.Add(test.Length/2-time.Minute), day, nil), "#%d", i)
+		}
+	}
+
+	// a second past 23:59:59 is the next day, not a leap second
+	leap := time.Date(2016, 12, 31, 23, 59, 60, 0, time.UTC)
+	assert.Equal(t, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), leap)
+	assert.Equal(t, time.Second, leap.Sub(time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC)))
+
+	// durations treat every minute as 60 seconds and every day as 24 hours
+	for i, test := range []struct {
+		Expr   string
+		Expect time.Duration
+	}{
+		{"1m", time.Second * 60},
+		{"61s", time.Minute + time.Second},
+		{"1d", time.Second * 86400},
+		{"1440m", day},
+	} {
+		v, err := ParseDuration(test.Expr)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	assert.Equal(t, "1m1s", FormatDuration(time.Second*61))
+	assert.Equal(t, "1d", FormatDuration(time.Second*86400))
+	assert.Equal(t, "23h59m59s", FormatDuration(time.Second*86399))
+}
+
+func TestAddExpr(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	leap := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		Time   time.Time
+		Expr   string
+		Expect time.Time
+		Err    bool
+	}{
+		{Time: leap, Expr: "1y", Expect: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Time: leap, Expr: "-1y", Expect: time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Time: leap, Expr: "4y", Expect: time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{Time: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), Expr: "1y", Expect: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, // 366 days
+		{Time: leap, Expr: "1mo", Expect: time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)},
+		{Time: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Expr: "1mo", Expect: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{Time: leap, Expr: "1y2mo3d", Expect: time.Date(2025, 5, 2, 12, 0, 0, 0, time.UTC)},
+		{Time: leap, Expr: "1mo2h30m", Expect: time.Date(2024, 3, 29, 14, 30, 0, 0, time.UTC)},
+		{Time: leap, Expr: "-1mo2h", Expect: time.Date(2024, 1, 29, 10, 0, 0, 0, time.UTC)},
+		{Time: leap, Expr: "+2w", Expect: time.Date(2024, 3, 14, 12, 0, 0, 0, time.UTC)},
+		{Time: leap, Expr: "1.5h", Expect: time.Date(2024, 2, 29, 13, 30, 0, 0, time.UTC)},
+		{ // a calendar day across the spring-forward transition is 23 hours
+			Time:   time.Date(2024, 3, 9, 9, 0, 0, 0, nyc),
+			Expr:   "1d",
+			Expect: time.Date(2024, 3, 10, 9, 0, 0, 0, nyc),
+		},
+		{Time: leap, Expr: "1.5y", Err: true},
+		{Time: leap, Expr: "1x", Err: true},
+		{Time: leap, Expr: "y", Err: true},
+		{Time: leap, Expr: "1", Err: true},
+		{Time: leap, Expr: "-", Err: true},
+		{Time: leap, Expr: "", Err: true},
+	}
+	for i, test := range tests {
+		v, err := AddExpr(test.Time, test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	_, err := AddExpr(leap, "1y3000000h")
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+	_, err = ParseDuration("1y")
+	assert.Error(t, err)
+	_, err = ParseDuration("1mo")
+	assert.Error(t, err)
+}
+
+func TestDefaultWeekStart(t *testing.T) {
+	defer func(ws time.Weekday) { DefaultWeekStart = ws }(DefaultWeekStart)
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	monday := time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		WeekStart time.Weekday
+		Start     time.Time
+	}{
+		{time.Monday, monday},
+		{time.Sunday, sunday},
+	}
+	for i, test := range tests {
+		DefaultWeekStart = test.WeekStart
+		end := test.Start.AddDate(0, 0, 7)
+		assert.Equal(t, test.Start, StartOfWeekDefault(ref), "#%d", i)
+		assert.Equal(t, end.Add(-time.Nanosecond), EndOfWeekDefault(ref), "#%d", i)
+		assert.Equal(t, test.Start, TruncateTo(ref, week, nil), "#%d", i)
+
+		v, err := ParseExprRef("eow", ref)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, end.Add(-time.Nanosecond), v, "#%d", i)
+		}
+		r, err := ParseRangeExprRef("this week", ref)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, TimeRange{Start: test.Start, End: end}, r, "#%d", i)
+		}
+
+		// explicit week starts are unaffected
+		assert.Equal(t, monday, StartOfWeek(ref, time.Monday), "#%d", i)
+		assert.Equal(t, sunday, StartOfWeek(ref, time.Sunday), "#%d", i)
+		ws := time.Monday
+		r, err = ParseRangeExprRefWith("this week", ref, ExprOptions{WeekStart: &ws})
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, monday, r.Start, "#%d", i)
+		}
+		assert.Equal(t, sunday, NewCalendar(time.UTC, time.Sunday).StartOfWeek(ref), "#%d", i)
+	}
+}
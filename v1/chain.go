@@ -0,0 +1,96 @@
+package timeutil
+
+import (
+	"time"
+)
+
+// Chain wraps a time to provide chainable calendar operations, for example:
+//
+//	Wrap(t).StartOfDay().AddDays(3).EndOfMonth().Time()
+//
+// Each method delegates to the standalone function of the same name.
+type Chain struct {
+	t time.Time
+}
+
+// Wrap returns a chain starting from the provided time.
+func Wrap(t time.Time) Chain {
+	return Chain{t: t}
+}
+
+// Time returns the time at the end of the chain.
+func (c Chain) Time() time.Time {
+	return c.t
+}
+
+// In converts the time to the provided location.
+func (c Chain) In(loc *time.Location) Chain {
+	return Chain{t: c.t.In(loc)}
+}
+
+// Add adds a duration to the time.
+func (c Chain) Add(d time.Duration) Chain {
+	return Chain{t: c.t.Add(d)}
+}
+
+// AddDays adds n calendar days to the time.
+func (c Chain) AddDays(n int) Chain {
+	return Chain{t: c.t.AddDate(0, 0, n)}
+}
+
+// AddMonths adds n calendar months to the time, normalizing it like
+// time.AddDate.
+func (c Chain) AddMonths(n int) Chain {
+	return Chain{t: c.t.AddDate(0, n, 0)}
+}
+
+// AddYears adds n calendar years to the time, normalizing it like
+// time.AddDate.
+func (c Chain) AddYears(n int) Chain {
+	return Chain{t: c.t.AddDate(n, 0, 0)}
+}
+
+// AddBusinessDays adds n business days to the time. See [AddBusinessDays].
+func (c Chain) AddBusinessDays(n int, holidays map[Date]bool) Chain {
+	return Chain{t: AddBusinessDays(c.t, n, holidays)}
+}
+
+// StartOfDay moves to the start of the day. See [StartOfDay].
+func (c Chain) StartOfDay() Chain {
+	return Chain{t: StartOfDay(c.t)}
+}
+
+// EndOfDay moves to the end of the day. See [EndOfDay].
+func (c Chain) EndOfDay() Chain {
+	return Chain{t: EndOfDay(c.t)}
+}
+
+// StartOfWeek moves to the start of the week. See [StartOfWeek].
+func (c Chain) StartOfWeek(weekStart time.Weekday) Chain {
+	return Chain{t: StartOfWeek(c.t, weekStart)}
+}
+
+// EndOfWeek moves to the end of the week. See [EndOfWeek].
+func (c Chain) EndOfWeek(weekStart time.Weekday) Chain {
+	return Chain{t: EndOfWeek(c.t, weekStart)}
+}
+
+// StartOfMonth moves to the start of the month. See [StartOfMonth].
+func (c Chain) StartOfMonth() Chain {
+	return Chain{t: StartOfMonth(c.t)}
+}
+
+// EndOfMonth moves to the end of the month. See [EndOfMonth].
+func (c Chain) EndOfMonth() Chain {
+	return Chain{t: EndOfMonth(c.t)}
+}
+
+// StartOfYear moves to the start of the year. See [StartOfYear].
+func (c Chain) StartOfYear() Chain {
+	return Chain{t: StartOfYear(c.t)}
+}
+
+// EndOfYear moves to the end of the year. See [EndOfYear].
+func (c Chain) EndOfYear() Chain {
+	return Chain{t: EndOfYear(c.t)}
+}
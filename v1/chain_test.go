@@ -0,0 +1,29 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	assert.Equal(t, ref, Wrap(ref).Time())
+	assert.Equal(t,
+		EndOfMonth(StartOfDay(ref).AddDate(0, 0, 20)),
+		Wrap(ref).StartOfDay().AddDays(20).EndOfMonth().Time(),
+	)
+	assert.Equal(t,
+		StartOfWeek(AddBusinessDays(ref, 3, nil), time.Monday),
+		Wrap(ref).AddBusinessDays(3, nil).StartOfWeek(time.Monday).Time(),
+	)
+	assert.Equal(t,
+		EndOfYear(StartOfMonth(ref.AddDate(0, 2, 0))),
+		Wrap(ref).AddMonths(2).StartOfMonth().EndOfYear().Time(),
+	)
+	assert.Equal(t,
+		time.Date(2025, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		Wrap(ref).AddMonths(2).StartOfMonth().EndOfYear().Time(),
+	)
+}
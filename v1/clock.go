@@ -0,0 +1,171 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock is a wall-clock time of day. If Location is nil, the clock is
+// interpreted in the location of whichever time it is applied to.
+type Clock struct {
+	Hour, Minute, Second int
+	Location             *time.Location
+}
+
+// On returns the wall-clock time described by the clock on the date of t.
+// The date is determined in the clock's location, if it has one, and the
+// result is expressed in that location.
+func (c Clock) On(t time.Time) time.Time {
+	if c.Location != nil {
+		t = t.In(c.Location)
+	}
+	return c.OnDate(DateOf(t), t.Location())
+}
+
+// OnDate returns the wall-clock time described by the clock on the provided
+// date in the provided location. The clock's own location is ignored.
+func (c Clock) OnDate(d Date, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, c.Hour, c.Minute, c.Second, 0, loc)
+}
+
+// TimeFromMidnight returns the wall-clock time secs seconds after midnight on
+// the date of t, in t's location, so 34200 is 09:30. The seconds are applied
+// to the wall clock rather than added as elapsed time, so on a day with a
+// daylight saving transition the result is still 09:30 even though more or
+// less time has actually elapsed since midnight. Values outside of a single
+// day roll over into adjacent days.
+func TimeFromMidnight(t time.Time, secs int) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, secs, 0, t.Location())
+}
+
+// ParseClock parses a wall-clock time of day. It accepts 24-hour times like
+// "17:30" or "17:30:15", 12-hour times with a suffix like "9am" or "5:30pm",
+// and the names "noon" and "midnight". Case is ignored. The resulting clock
+// has no location.
+func ParseClock(s string) (Clock, error) {
+	if c, ok := parseClock(s); ok {
+		return c, nil
+	}
+	return Clock{}, fmt.Errorf("Invalid time of day: %q", s)
+}
+
+// parseClock parses a time of day like [ParseClock], without the cost of
+// constructing an error when the input is not a time of day.
+func parseClock(s string) (Clock, bool) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	switch v {
+	case "noon":
+		return Clock{Hour: 12}, true
+	case "midnight":
+		return Clock{}, true
+	}
+	var pm, twelve bool
+	if strings.HasSuffix(v, "am") || strings.HasSuffix(v, "pm") {
+		pm, twelve = v[len(v)-2] == 'p', true
+		v = v[:len(v)-2]
+	}
+	f := strings.Split(v, ":")
+	if len(f) > 3 || (len(f) == 1 && !twelve) {
+		return Clock{}, false
+	}
+	var n [3]int
+	for i, e := range f {
+		if len(e) < 1 || len(e) > 2 || (i > 0 && len(e) != 2) {
+			return Clock{}, false
+		}
+		x, err := strconv.ParseUint(e, 10, 8)
+		if err != nil {
+			return Clock{}, false
+		}
+		n[i] = int(x)
+	}
+	c := Clock{Hour: n[0], Minute: n[1], Second: n[2]}
+	if twelve {
+		if c.Hour < 1 || c.Hour > 12 {
+			return Clock{}, false
+		}
+		c.Hour %= 12
+		if pm {
+			c.Hour += 12
+		}
+	}
+	if c.Hour > 23 || c.Minute > 59 || c.Second > 59 {
+		return Clock{}, false
+	}
+	return c, true
+}
+
+// parseClockPhrase parses an English time-of-day phrase, like "half past 9",
+// "quarter past noon", "quarter to 5", or "3 o'clock", and returns that time
+// on the day of ref. Hours are given on a 12-hour clock without a suffix, so
+// they are ambiguous; the earlier of the two candidates which is not before
+// ref is chosen, or the later of them if both have already passed on ref's
+// day. The names "noon" and "midnight" are not ambiguous.
+func parseClockPhrase(s string, ref time.Time) (time.Time, bool) {
+	f := strings.Fields(strings.ToLower(s))
+	var min int
+	var h string
+	switch {
+	case len(f) == 2 && (f[1] == "o'clock" || f[1] == "oclock"):
+		h = f[0]
+	case len(f) == 3 && f[0] == "half" && f[1] == "past":
+		h, min = f[2], 30
+	case len(f) == 3 && f[0] == "quarter" && f[1] == "past":
+		h, min = f[2], 15
+	case len(f) == 3 && f[0] == "quarter" && f[1] == "to":
+		h, min = f[2], -15
+	default:
+		return time.Time{}, false
+	}
+	var hours []int
+	switch h {
+	case "noon":
+		hours = []int{12}
+	case "midnight":
+		if min < 0 {
+			hours = []int{24} // quarter to midnight is the end of ref's day
+		} else {
+			hours = []int{0}
+		}
+	default:
+		n, err := strconv.Atoi(h)
+		if err != nil || n < 1 || n > 12 {
+			return time.Time{}, false
+		}
+		if min < 0 {
+			// quarter to 12 is 11:45 or 23:45, so the hours are taken as
+			// 1-12 and 13-24 before the minutes are subtracted
+			hours = []int{n, n + 12}
+		} else {
+			hours = []int{n % 12, n%12 + 12}
+		}
+	}
+	d := DateOf(ref)
+	var t time.Time
+	for _, e := range hours {
+		t = time.Date(d.Year, d.Month, d.Day, e, min, 0, 0, ref.Location())
+		if !t.Before(ref) {
+			break
+		}
+	}
+	return t, true
+}
+
+// parseMidnightSeconds parses a time of day expressed as seconds since
+// midnight with the prefix "tod:", like "tod:34200", which is required to
+// distinguish it from other numeric forms. The seconds must fall within a
+// single day.
+func parseMidnightSeconds(s string) (int, bool) {
+	v, ok := strings.CutPrefix(strings.ToLower(s), "tod:")
+	if !ok || v == "" || strings.Trim(v, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n >= 24*60*60 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,66 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect Clock
+		Err    bool
+	}{
+		{Expr: "9am", Expect: Clock{Hour: 9}},
+		{Expr: "9AM", Expect: Clock{Hour: 9}},
+		{Expr: "9pm", Expect: Clock{Hour: 21}},
+		{Expr: "12am", Expect: Clock{Hour: 0}},
+		{Expr: "12pm", Expect: Clock{Hour: 12}},
+		{Expr: "5:30pm", Expect: Clock{Hour: 17, Minute: 30}},
+		{Expr: "17:30", Expect: Clock{Hour: 17, Minute: 30}},
+		{Expr: "09:30", Expect: Clock{Hour: 9, Minute: 30}},
+		{Expr: "17:30:15", Expect: Clock{Hour: 17, Minute: 30, Second: 15}},
+		{Expr: "0:00", Expect: Clock{}},
+		{Expr: "noon", Expect: Clock{Hour: 12}},
+		{Expr: "Midnight", Expect: Clock{}},
+		{Expr: "9", Err: true},
+		{Expr: "13pm", Err: true},
+		{Expr: "0am", Err: true},
+		{Expr: "24:00", Err: true},
+		{Expr: "17:60", Err: true},
+		{Expr: "17:5", Err: true},
+		{Expr: "17:30:15:00", Err: true},
+		{Expr: "am", Err: true},
+		{Expr: "", Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseClock(test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestTimeFromMidnight(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	tests := []struct {
+		Time   time.Time
+		Secs   int
+		Expect time.Time
+	}{
+		{time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), 34200, time.Date(2024, 11, 14, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), 0, time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), 86400 + 60, time.Date(2024, 11, 15, 0, 1, 0, 0, time.UTC)},
+		{time.Date(2024, 3, 10, 12, 0, 0, 0, nyc), 34200, time.Date(2024, 3, 10, 9, 30, 0, 0, nyc)}, // spring forward
+		{time.Date(2024, 11, 3, 12, 0, 0, 0, nyc), 34200, time.Date(2024, 11, 3, 9, 30, 0, 0, nyc)}, // fall back
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, TimeFromMidnight(test.Time, test.Secs), "#%d", i)
+	}
+	v := TimeFromMidnight(time.Date(2024, 3, 10, 12, 0, 0, 0, nyc), 34200)
+	assert.Equal(t, time.Hour*8+time.Minute*30, v.Sub(StartOfDay(v))) // an hour was skipped
+}
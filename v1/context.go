@@ -0,0 +1,25 @@
+package timeutil
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeoutDuration is a convenience wrapper around [context.WithTimeout]
+// which accepts a [Duration].
+func WithTimeoutDuration(parent context.Context, d Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, time.Duration(d))
+}
+
+// DeadlineFromExpr parses a time expression via [ParseExpr] and derives a
+// context from the parent which has the resulting time as its deadline. If
+// the expression cannot be parsed, an error is returned and no context is
+// created.
+func DeadlineFromExpr(parent context.Context, expr string) (context.Context, context.CancelFunc, error) {
+	t, err := ParseExpr(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithDeadline(parent, t)
+	return ctx, cancel, nil
+}
@@ -0,0 +1,45 @@
+package timeutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeoutDuration(t *testing.T) {
+	before := time.Now()
+	ctx, cancel := WithTimeoutDuration(context.Background(), Duration(time.Hour))
+	defer cancel()
+	v, ok := ctx.Deadline()
+	if assert.True(t, ok) {
+		assert.False(t, v.Before(before.Add(time.Hour)))
+		assert.False(t, v.After(time.Now().Add(time.Hour)))
+	}
+}
+
+func TestDeadlineFromExpr(t *testing.T) {
+	ref := time.Now().Add(time.Hour).Truncate(time.Second)
+	defer func(f func() time.Time) { Now = f }(Now)
+	Now = func() time.Time { return ref }
+
+	ctx, cancel, err := DeadlineFromExpr(context.Background(), "+90m")
+	if assert.NoError(t, err) {
+		defer cancel()
+		v, ok := ctx.Deadline()
+		if assert.True(t, ok) {
+			assert.Equal(t, ref.Add(time.Minute*90), v)
+		}
+		assert.NoError(t, ctx.Err())
+	}
+
+	ctx, cancel, err = DeadlineFromExpr(context.Background(), "@0")
+	if assert.NoError(t, err) {
+		defer cancel()
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	}
+
+	_, _, err = DeadlineFromExpr(context.Background(), "???")
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package timeutil
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	durationType    = reflect.TypeOf(Duration(0))
+	stdDurationType = reflect.TypeOf(time.Duration(0))
+)
+
+// DurationDecodeHook returns a function compatible with mapstructure's
+// DecodeHookFuncType which converts configuration values into a [Duration]
+// or a time.Duration. Values for any other target type are passed through
+// unchanged.
+//
+// Values are interpreted as follows:
+//   - strings are parsed with [ParseDuration];
+//   - integers are interpreted as a count of intUnit, typically time.Second
+//     or time.Nanosecond; if intUnit is not positive, time.Second is used;
+//   - floats are always interpreted as seconds, and may have a fraction;
+//   - values which are already durations are converted as-is.
+//
+// An integer or float which would overflow a duration is an error.
+func DurationDecodeHook(intUnit time.Duration) func(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if intUnit <= 0 {
+		intUnit = time.Second
+	}
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != durationType && to != stdDurationType {
+			return data, nil
+		}
+		d, err := decodeDuration(data, intUnit)
+		if err != nil {
+			return nil, err
+		}
+		if to == durationType {
+			return Duration(d), nil
+		} else {
+			return d, nil
+		}
+	}
+}
+
+func decodeDuration(data interface{}, intUnit time.Duration) (time.Duration, error) {
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.String:
+		return ParseDuration(strings.TrimSpace(v.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == stdDurationType || v.Type() == durationType {
+			return time.Duration(v.Int()), nil
+		}
+		d, ok := MulDuration(intUnit, v.Int())
+		if !ok {
+			return 0, fmt.Errorf("time: duration overflows: %d", v.Int())
+		}
+		return d, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("time: duration overflows: %d", v.Uint())
+		}
+		d, ok := MulDuration(intUnit, int64(v.Uint()))
+		if !ok {
+			return 0, fmt.Errorf("time: duration overflows: %d", v.Uint())
+		}
+		return d, nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float() * float64(time.Second)
+		if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+			return 0, fmt.Errorf("time: duration overflows: %v", v.Float())
+		}
+		return time.Duration(f), nil
+	default:
+		return 0, fmt.Errorf("time: cannot decode %T as a duration", data)
+	}
+}
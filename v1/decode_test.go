@@ -0,0 +1,45 @@
+package timeutil
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationDecodeHook(t *testing.T) {
+	tests := []struct {
+		Unit   time.Duration
+		Data   interface{}
+		To     reflect.Type
+		Expect interface{}
+		Err    bool
+	}{
+		{Unit: time.Second, Data: "1h30m", To: durationType, Expect: Duration(time.Minute * 90)},
+		{Unit: time.Second, Data: "1d", To: stdDurationType, Expect: day},
+		{Unit: time.Second, Data: 90, To: durationType, Expect: Duration(time.Second * 90)},
+		{Unit: time.Second, Data: int64(90), To: stdDurationType, Expect: time.Second * 90},
+		{Unit: time.Second, Data: uint8(90), To: durationType, Expect: Duration(time.Second * 90)},
+		{Unit: time.Nanosecond, Data: 90, To: durationType, Expect: Duration(90)},
+		{Unit: 0, Data: 90, To: durationType, Expect: Duration(time.Second * 90)},
+		{Unit: time.Second, Data: 1.5, To: durationType, Expect: Duration(time.Millisecond * 1500)},
+		{Unit: time.Nanosecond, Data: float32(0.25), To: durationType, Expect: Duration(time.Millisecond * 250)},
+		{Unit: time.Second, Data: time.Minute, To: durationType, Expect: Duration(time.Minute)},
+		{Unit: time.Second, Data: "1h", To: reflect.TypeOf(""), Expect: "1h"},
+		{Unit: time.Second, Data: "1x", To: durationType, Err: true},
+		{Unit: time.Second, Data: math.MaxInt64, To: durationType, Err: true},
+		{Unit: time.Second, Data: 1e20, To: durationType, Err: true},
+		{Unit: time.Second, Data: true, To: durationType, Err: true},
+	}
+	for i, test := range tests {
+		hook := DurationDecodeHook(test.Unit)
+		v, err := hook(reflect.TypeOf(test.Data), test.To, test.Data)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
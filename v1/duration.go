@@ -1,16 +1,25 @@
 package timeutil
 
 import (
+	"encoding/binary"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Duration time.Duration
 
+// MarshalJSON formats the duration as a string with full precision, so it
+// is not affected by [DefaultSmallestUnit].
 func (d Duration) MarshalJSON() ([]byte, error) {
-	return json.Marshal(FormatDuration(time.Duration(d)))
+	return json.Marshal(FormatDurationWith(time.Duration(d), FormatOptions{}))
 }
 
 func (d *Duration) UnmarshalJSON(data []byte) error {
@@ -27,6 +36,72 @@ func (d *Duration) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (d Duration) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(FormatDurationWith(time.Duration(d), FormatOptions{}), start)
+}
+
+func (d *Duration) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var s string
+	err := dec.DecodeElement(&s, &start)
+	if err != nil {
+		return err
+	}
+	v, err := ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: FormatDurationWith(time.Duration(d), FormatOptions{})}, nil
+}
+
+func (d *Duration) UnmarshalXMLAttr(attr xml.Attr) error {
+	v, err := ParseDuration(strings.TrimSpace(attr.Value))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalBinary encodes the duration as its nanosecond count, in eight
+// big-endian bytes.
+func (d Duration) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, uint64(d)), nil
+}
+
+func (d *Duration) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("time: invalid binary duration length %d, expected 8", len(data))
+	}
+	*d = Duration(binary.BigEndian.Uint64(data))
+	return nil
+}
+
+// LessThan reports whether d is shorter than o.
+func (d Duration) LessThan(o Duration) bool {
+	return d < o
+}
+
+// GreaterThan reports whether d is longer than o.
+func (d Duration) GreaterThan(o Duration) bool {
+	return d > o
+}
+
+// Equal reports whether d and o are the same duration.
+func (d Duration) Equal(o Duration) bool {
+	return d == o
+}
+
+// Abs returns the absolute value of d. As a special case, the minimum
+// duration, which cannot be negated, is saturated to the maximum duration.
+func (d Duration) Abs() Duration {
+	return Duration(time.Duration(d).Abs())
+}
+
 const (
 	lowerhex  = "0123456789abcdef"
 	runeSelf  = 0x80
@@ -51,7 +126,352 @@ var unitMap = map[string]uint64{
 // units:
 //   - d: days (defined as 24 hours)
 //   - w: weeks (defined as 7 days)
+//
+// A single leading sign is permitted. A sign alone, like "-", or repeated
+// signs, like "--1s", are invalid. A bare "0" is the only number which may
+// omit its unit, with or without a sign, so "-0", "+0", and "-0s" are all
+// zero, while "0.0" is missing a unit. A number may omit the digits on one
+// side of its decimal point but not both, so "+.5h" is 30 minutes and ".s"
+// is invalid.
+//
+// If the duration is too large to be represented, the error wraps
+// [ErrDurationOverflow].
+//
+// Years and months vary in length, so they are not supported as units of a
+// fixed duration. Use [AddExpr] to step a time by calendar years and months.
 func ParseDuration(s string) (time.Duration, error) {
+	return parseDuration(s, false)
+}
+
+// ParseDurationStrict parses a duration string like [ParseDuration], except
+// that the approximated units coarser than hours, like "d" and "w", are
+// rejected. This is equivalent to time.ParseDuration.
+func ParseDurationStrict(s string) (time.Duration, error) {
+	return parseDuration(s, true)
+}
+
+// ParseDurationChecked parses a duration string like [ParseDuration] and
+// also reports whether it is approximate, which is when it uses any of the
+// units "d" or "w". These are defined as exactly 24 hours and 7 days, which
+// only approximate calendar days and weeks across daylight saving
+// transitions, so callers may want to warn about them or use [AddExpr]
+// instead. The value is the same as that returned by ParseDuration. Months
+// and years are not accepted at all, as with ParseDuration.
+func ParseDurationChecked(s string) (d time.Duration, approximate bool, err error) {
+	d, err = ParseDuration(s)
+	if err != nil {
+		return 0, false, err
+	}
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] == '.' || s[i] == '-' || s[i] == '+' || '0' <= s[i] && s[i] <= '9') {
+			i++
+		}
+		s = s[i:]
+		i = 0
+		for i < len(s) && s[i] != '.' && (s[i] < '0' || s[i] > '9') {
+			i++
+		}
+		switch s[:i] {
+		case "d", "w":
+			approximate = true
+		}
+		s = s[i:]
+	}
+	return d, approximate, nil
+}
+
+// humanUnits maps long-form and abbreviated unit names accepted by
+// ParseDurationHuman to their canonical units.
+var humanUnits = map[string]string{
+	"sec":     "s",
+	"secs":    "s",
+	"second":  "s",
+	"seconds": "s",
+	"min":     "m",
+	"mins":    "m",
+	"minute":  "m",
+	"minutes": "m",
+	"hr":      "h",
+	"hrs":     "h",
+	"hour":    "h",
+	"hours":   "h",
+	"day":     "d",
+	"days":    "d",
+	"week":    "w",
+	"weeks":   "w",
+}
+
+// ParseDurationHuman parses a duration string like [ParseDuration], but is
+// more forgiving of human input. Whitespace is permitted between components
+// and between a number and its unit, units are case-insensitive, and the
+// following long-form unit names are accepted:
+//   - sec, secs, second, seconds: seconds
+//   - min, mins, minute, minutes: minutes
+//   - hr, hrs, hour, hours: hours
+//   - day, days: days
+//   - week, weeks: weeks
+//
+// For example, "1 hour 30 mins" and "1h 30m" are both 90 minutes. Components
+// may also be joined by commas, the word "and", or both, as in "1 hour and
+// 30 minutes" or "2 days, 3 hours". An approximate duration, with a leading "~"
+// or "about", like "~5m" or "about 2 hours", is parsed as the exact duration.
+func ParseDurationHuman(s string) (time.Duration, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "~") {
+		s = strings.TrimSpace(s[1:])
+	} else if len(s) > 5 && strings.EqualFold(s[:5], "about") && unicode.IsSpace(rune(s[5])) {
+		s = strings.TrimSpace(s[5:])
+	}
+
+	var b strings.Builder
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		b.WriteByte(s[0])
+		s = strings.TrimSpace(s[1:])
+	}
+	for s != "" {
+		n := strings.IndexFunc(s, func(r rune) bool {
+			return r != '.' && (r < '0' || r > '9')
+		})
+		if n < 0 {
+			n = len(s)
+		}
+		b.WriteString(s[:n])
+		s = strings.TrimSpace(s[n:])
+
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return r == '.' || r == ',' || '0' <= r && r <= '9' || unicode.IsSpace(r)
+		})
+		if i < 0 {
+			i = len(s)
+		}
+		if n == 0 && i == 0 {
+			return 0, errors.New("time: invalid duration " + quote(orig))
+		}
+		u := strings.ToLower(s[:i])
+		if c, ok := humanUnits[u]; ok {
+			u = c
+		} else if _, ok := unitMap[u]; !ok && u != "" {
+			return 0, errors.New("time: unknown unit " + quote(s[:i]) + " in duration " + quote(orig))
+		}
+		b.WriteString(u)
+		s = strings.TrimSpace(s[i:])
+
+		// a number without a unit would otherwise be joined onto the next
+		// number, so it is only permitted as a bare zero
+		if u == "" && (s != "" || strings.TrimLeft(b.String(), "+-") != "0") {
+			return 0, errors.New("time: missing unit in duration " + quote(orig))
+		}
+		if u != "" {
+			if r, ok := cutJoiner(s); ok {
+				if r == "" {
+					return 0, errors.New("time: invalid duration " + quote(orig))
+				}
+				s = r
+			}
+		}
+	}
+
+	d, err := ParseDuration(b.String())
+	if errors.Is(err, ErrDurationOverflow) {
+		return 0, overflowError(orig)
+	} else if err != nil {
+		return 0, errors.New("time: invalid duration " + quote(orig))
+	}
+	return d, nil
+}
+
+// cutJoiner removes a joiner between the components of a human duration,
+// which is a comma, the word "and", or both, from the beginning of s.
+func cutJoiner(s string) (string, bool) {
+	var ok bool
+	if strings.HasPrefix(s, ",") {
+		s, ok = strings.TrimSpace(s[1:]), true
+	}
+	if len(s) >= 3 && strings.EqualFold(s[:3], "and") && (len(s) == 3 || unicode.IsSpace(rune(s[3]))) {
+		s, ok = strings.TrimSpace(s[3:]), true
+	}
+	return s, ok
+}
+
+// ParseDurationAs parses a duration string like [ParseDuration], except that
+// a bare number without any unit, like "90" or "1.5", is interpreted as a
+// count of the provided unit. A number with a unit is always interpreted
+// according to its own unit, so with a unit of time.Second, "90" and "1.5m"
+// are both 90 seconds. The unit must be one of the units supported by
+// [ParseDuration].
+func ParseDurationAs(s string, unit time.Duration) (time.Duration, error) {
+	name, ok := unitNames[unit]
+	if !ok {
+		return 0, errors.New("time: unsupported default unit " + unit.String() + " for duration " + quote(s))
+	}
+	v := s
+	if v != "" && (v[0] == '-' || v[0] == '+') {
+		v = v[1:]
+	}
+	if v != "" && strings.Trim(v, "0123456789.") == "" {
+		s += name
+	}
+	return ParseDuration(s)
+}
+
+// ParseDurationRange parses a duration string like [ParseDuration] and checks
+// that the result is within the inclusive bounds [min, max]. If it is not, the
+// error names the bound which was violated.
+func ParseDurationRange(s string, min, max time.Duration) (time.Duration, error) {
+	d, err := ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < min {
+		return 0, errors.New("time: duration " + quote(s) + " is less than the minimum of " + FormatDurationWith(min, FormatOptions{}))
+	}
+	if d > max {
+		return 0, errors.New("time: duration " + quote(s) + " is greater than the maximum of " + FormatDurationWith(max, FormatOptions{}))
+	}
+	return d, nil
+}
+
+// ParseDurationLoose parses a duration string like [ParseDuration], but also
+// accepts numbers in scientific notation, such as "1e3ms" or "2.5e2s".
+func ParseDurationLoose(s string) (time.Duration, error) {
+	orig := s
+
+	var b strings.Builder
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		b.WriteByte(s[0])
+		s = s[1:]
+	}
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] == '.' || '0' <= s[i] && s[i] <= '9') {
+			i++
+		}
+		n := s[:i]
+		s = s[i:]
+		if s != "" && (s[0] == 'e' || s[0] == 'E') {
+			j := 1
+			if j < len(s) && (s[j] == '-' || s[j] == '+') {
+				j++
+			}
+			k := j
+			for k < len(s) && '0' <= s[k] && s[k] <= '9' {
+				k++
+			}
+			if k == j || n == "" || n == "." {
+				return 0, errors.New("time: invalid duration " + quote(orig))
+			}
+			exp, err := strconv.Atoi(s[1:k])
+			if err != nil {
+				return 0, errors.New("time: invalid duration " + quote(orig))
+			}
+			v, ok := shiftDecimal(n, exp)
+			if !ok {
+				return 0, overflowError(orig)
+			}
+			n, s = v, s[k:]
+		}
+		b.WriteString(n)
+		i = 0
+		for i < len(s) && s[i] != '.' && (s[i] < '0' || s[i] > '9') {
+			i++
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+	}
+
+	d, err := ParseDuration(b.String())
+	if errors.Is(err, ErrDurationOverflow) {
+		return 0, overflowError(orig)
+	} else if err != nil {
+		return 0, errors.New("time: invalid duration " + quote(orig))
+	}
+	return d, nil
+}
+
+// ParseDurationUnicode parses a duration string like [ParseDuration], but
+// also accepts decimal digits from any script, such as the fullwidth "１０m"
+// digits or the Arabic-Indic "٣٠s", which are normalized to their ASCII
+// equivalents before parsing. Units and other characters are not normalized.
+func ParseDurationUnicode(s string) (time.Duration, error) {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return ParseDuration(s)
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if v, ok := digitValue(r); ok {
+			b.WriteByte(byte('0' + v))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	d, err := ParseDuration(b.String())
+	if errors.Is(err, ErrDurationOverflow) {
+		return 0, overflowError(s)
+	} else if err != nil {
+		return 0, errors.New("time: invalid duration " + quote(s))
+	}
+	return d, nil
+}
+
+// digitValue returns the value of a Unicode decimal digit. Decimal digits are
+// encoded in contiguous runs from zero to nine, so the value is the offset of
+// the rune within its run.
+func digitValue(r rune) (int, bool) {
+	if '0' <= r && r <= '9' {
+		return int(r - '0'), true
+	}
+	if r < utf8.RuneSelf || !unicode.IsDigit(r) {
+		return 0, false
+	}
+	for _, v := range unicode.Nd.R16 {
+		if rune(v.Lo) <= r && r <= rune(v.Hi) {
+			return int(r-rune(v.Lo)) % 10, true
+		}
+	}
+	for _, v := range unicode.Nd.R32 {
+		if rune(v.Lo) <= r && r <= rune(v.Hi) {
+			return int(r-rune(v.Lo)) % 10, true
+		}
+	}
+	return 0, false
+}
+
+// shiftDecimal multiplies the decimal number s by 10^exp by moving its decimal
+// point, returning the result as a decimal number without an exponent. If the
+// result is too large to be a valid duration in any unit, ok is false.
+func shiftDecimal(s string, exp int) (string, bool) {
+	w, f, _ := strings.Cut(s, ".")
+	digits, p := w+f, len(w)+exp
+	for digits != "" && digits[0] == '0' {
+		digits = digits[1:]
+		p--
+	}
+	switch {
+	case digits == "":
+		return "0", true
+	case p > 19: // at least 10^19, which overflows even in nanoseconds
+		return "", false
+	case p < -24: // less than 10^-24, which is less than 1ns even in weeks
+		return "0", true
+	case p <= 0:
+		return "0." + strings.Repeat("0", -p) + digits, true
+	case p >= len(digits):
+		return digits + strings.Repeat("0", p-len(digits)), true
+	default:
+		return digits[:p] + "." + digits[p:], true
+	}
+}
+
+func parseDuration(s string, strict bool) (time.Duration, error) {
 	// [-+]?([0-9]*(\.[0-9]*)?[a-z]+)+
 	orig := s
 	var d uint64
@@ -88,7 +508,7 @@ func ParseDuration(s string) (time.Duration, error) {
 		pl := len(s)
 		v, s, err = leadingInt(s)
 		if err != nil {
-			return 0, errors.New("time: invalid duration " + quote(orig))
+			return 0, overflowError(orig)
 		}
 		pre := pl != len(s) // whether we consumed anything before a period
 
@@ -122,9 +542,11 @@ func ParseDuration(s string) (time.Duration, error) {
 		if !ok {
 			return 0, errors.New("time: unknown unit " + quote(u) + " in duration " + quote(orig))
 		}
+		if strict && unit > uint64(time.Hour) {
+			return 0, errors.New("time: approximate unit " + quote(u) + " not allowed in duration " + quote(orig))
+		}
 		if v > 1<<63/unit {
-			// overflow
-			return 0, errors.New("time: invalid duration " + quote(orig))
+			return 0, overflowError(orig)
 		}
 		v *= unit
 		if f > 0 {
@@ -132,26 +554,97 @@ func ParseDuration(s string) (time.Duration, error) {
 			// v >= 0 && (f*unit/scale) <= 3.6e+12 (ns/h, h is the largest unit)
 			v += uint64(float64(f) * (float64(unit) / scale))
 			if v > 1<<63 {
-				// overflow
-				return 0, errors.New("time: invalid duration " + quote(orig))
+				return 0, overflowError(orig)
 			}
 		}
 		d += v
 		if d > 1<<63 {
-			return 0, errors.New("time: invalid duration " + quote(orig))
+			return 0, overflowError(orig)
 		}
 	}
 	if neg {
 		return -time.Duration(d), nil
 	}
 	if d > 1<<63-1 {
-		return 0, errors.New("time: invalid duration " + quote(orig))
+		return 0, overflowError(orig)
 	}
 	return time.Duration(d), nil
 }
 
+// ParseDurationPrefix parses a duration, as accepted by [ParseDuration], from
+// the beginning of a string and returns it along with the remaining text.
+// It consumes as many number and unit groups as it can and stops at the
+// first one which is not valid, so "1h30m then do X" is 90 minutes with the
+// remainder " then do X". A unit is the whole run of letters following a
+// number, so "1hour" does not begin with a duration. If the string does not
+// begin with a duration, an error is returned along with the whole string.
+func ParseDurationPrefix(s string) (time.Duration, string, error) {
+	i := 0
+	if i < len(s) && (s[i] == '-' || s[i] == '+') {
+		i++
+	}
+	n := 0 // the end of the last complete group
+	for j := i; j < len(s); {
+		k := j
+		for k < len(s) && ('0' <= s[k] && s[k] <= '9' || s[k] == '.') {
+			k++
+		}
+		if k == j {
+			break
+		}
+		u := strings.IndexFunc(s[k:], func(r rune) bool { return !unicode.IsLetter(r) })
+		if u < 0 {
+			u = len(s) - k
+		}
+		if _, ok := unitMap[s[k:k+u]]; !ok {
+			break
+		}
+		j = k + u
+		n = j
+	}
+	if n == 0 {
+		return 0, s, errors.New("time: invalid duration prefix in " + quote(s))
+	}
+	d, err := ParseDuration(s[:n])
+	if err != nil {
+		return 0, s, err
+	}
+	return d, s[n:], nil
+}
+
+// ParseDurations parses a list of durations separated by sep, such as
+// "1h,30m,2d". Whitespace around each element is ignored. If any element is
+// empty or cannot be parsed, an error identifying its index is returned.
+func ParseDurations(s, sep string) ([]time.Duration, error) {
+	f := strings.Split(s, sep)
+	r := make([]time.Duration, len(f))
+	for i, e := range f {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			return nil, fmt.Errorf("time: empty duration at index %d in list %s", i, quote(s))
+		}
+		v, err := ParseDuration(e)
+		if err != nil {
+			return nil, fmt.Errorf("time: invalid duration at index %d in list: %w", i, err)
+		}
+		r[i] = v
+	}
+	return r, nil
+}
+
 var errLeadingInt = errors.New("time: bad [0-9]*") // never printed
 
+// ErrDurationOverflow is wrapped by the errors returned when parsing a
+// duration which is too large to be represented, so that it can be
+// distinguished from a syntax error with errors.Is.
+var ErrDurationOverflow = errors.New("time: value too large")
+
+// overflowError returns an error which wraps [ErrDurationOverflow] for the
+// provided duration string.
+func overflowError(s string) error {
+	return fmt.Errorf("%w in duration %s", ErrDurationOverflow, quote(s))
+}
+
 // leadingInt consumes the leading [0-9]* from s.
 func leadingInt(s string) (x uint64, rem string, err error) {
 	i := 0
@@ -240,68 +733,294 @@ func quote(s string) string {
 	return string(buf)
 }
 
-const day = time.Hour * 24
+const (
+	day  = time.Hour * 24
+	week = day * 7
+)
 
-func formatHigh(d time.Duration) string {
-	var v time.Duration
-	var f string
+func formatHigh(d uint64) []string {
+	var v uint64
+	var f []string
 
-	d = d / time.Second
+	d = d / uint64(time.Second)
 	v = d % 60
 	if v != 0 {
-		f = fmt.Sprintf("%ds", v) + f
+		f = append([]string{fmt.Sprintf("%ds", v)}, f...)
 	}
 
 	d /= 60
 	v = d % 60
 	if v != 0 {
-		f = fmt.Sprintf("%dm", v) + f
+		f = append([]string{fmt.Sprintf("%dm", v)}, f...)
 	}
 
 	d /= 60
 	v = d % 24
 	if v != 0 {
-		f = fmt.Sprintf("%dh", v) + f
+		f = append([]string{fmt.Sprintf("%dh", v)}, f...)
 	}
 
 	d /= 24
 	if d > 0 {
-		f = fmt.Sprintf("%dd", d) + f
+		f = append([]string{fmt.Sprintf("%dd", d)}, f...)
 	}
 
 	return f
 }
 
-func formatLow(d time.Duration) string {
-	var v time.Duration
-	var f string
+func formatLow(d uint64, micro string) []string {
+	var v uint64
+	var f []string
 
-	d = d % time.Second
+	d = d % uint64(time.Second)
 	v = d % 1000
 	if v != 0 {
-		f = fmt.Sprintf("%dns", v) + f
+		f = append([]string{fmt.Sprintf("%dns", v)}, f...)
 	}
 
 	d /= 1000
 	v = d % 1000
 	if v != 0 {
-		f = fmt.Sprintf("%dµs", v) + f
+		f = append([]string{fmt.Sprintf("%d%s", v, micro)}, f...)
 	}
 
 	d /= 1000
 	if d > 0 {
-		f = fmt.Sprintf("%dms", d) + f
+		f = append([]string{fmt.Sprintf("%dms", d)}, f...)
 	}
 
 	return f
 }
 
+// FormatOptions configures how durations are formatted.
+type FormatOptions struct {
+	// ASCII selects the ASCII "us" suffix for microseconds instead of the
+	// default "µs" (U+00B5). Both forms are accepted by [ParseDuration].
+	ASCII bool
+	// Separator is inserted between components, so a separator of " " formats
+	// "3d2h5m" as "3d 2h 5m". The default is no separator. Separated output
+	// can be read back by [ParseDurationHuman] but not [ParseDuration].
+	Separator string
+	// SmallestUnit is the smallest unit which is displayed; any remainder
+	// smaller than it is truncated. The default is time.Nanosecond.
+	SmallestUnit time.Duration
+	// Floor, if not empty, is displayed instead of "0s" when a non-zero
+	// duration is truncated to zero by SmallestUnit, so that it can be
+	// distinguished from a true zero. For example, "<1ms".
+	Floor string
+	// Units, if not empty, lists the exact units to display, from largest to
+	// smallest, by the suffixes accepted by [ParseDuration]. Every listed
+	// unit is displayed even if it is zero, and each is zero-padded to the
+	// width of the largest value it can take before carrying into the next
+	// larger unit, so that {"h", "m", "s"} with a separator of " " formats 90
+	// seconds as "00h 01m 30s". The largest unit absorbs the whole of its
+	// part of the duration and may be wider. Any remainder smaller than the
+	// smallest unit is truncated. Unknown units are ignored.
+	Units []string
+	// Negative is the style in which negative durations are displayed. The
+	// default is NegativeLeadingMinus, which is the only style that can be
+	// read back by [ParseDuration].
+	Negative NegativeStyle
+	// Style selects the overall form of the output. The default is
+	// StyleCompact. ASCII, Separator, Units and Grouping only apply to
+	// StyleCompact.
+	Style FormatStyle
+	// Grouping, if not empty, is inserted between every three digits of the
+	// largest component, so a grouping of "," formats 12345 days as
+	// "12,345d". Smaller components never need grouping. The default is no
+	// grouping. Grouped output cannot be read back by [ParseDuration].
+	Grouping string
+}
+
+// FormatStyle describes the overall form in which a duration is displayed.
+type FormatStyle int
+
+const (
+	StyleCompact FormatStyle = iota // a sequence of components, like "1d2h3m"
+	StyleISO8601                    // an ISO 8601 duration, like "PT26H3M"
+	StyleClock                      // a clock reading, like "26:03:00"
+)
+
+// NegativeStyle describes how a negative duration is displayed.
+type NegativeStyle int
+
+const (
+	NegativeLeadingMinus  NegativeStyle = iota // a leading minus, like "-1h30m"
+	NegativeParenthesized                      // a minus and parentheses, like "-(1h30m)"
+	NegativeAgoSuffix                          // an "ago" suffix, like "1h30m ago"
+)
+
+// FormatDuration formats a duration as a compact sequence of components, such
+// as "1d2h3m", which can be read back by [ParseDuration]. Components which
+// are zero are omitted wherever they fall, so one day and five seconds is
+// formatted as "1d5s". Negative durations are formatted as their magnitude
+// with a leading "-", like "-1d2h", which applies to every component. This
+// holds for the minimum duration as well.
+//
+// Any remainder smaller than [DefaultSmallestUnit] is truncated.
 func FormatDuration(d time.Duration) string {
-	if d == 0 {
+	return FormatDurationWith(d, FormatOptions{SmallestUnit: DefaultSmallestUnit})
+}
+
+// DefaultSmallestUnit is the smallest unit displayed by [FormatDuration]. It
+// is time.Nanosecond by default, so no precision is lost, but it may be set
+// to a coarser unit like time.Millisecond to omit noise from logs. It is
+// global state: it should be set once at startup, before any formatting
+// happens, and it applies to every caller of FormatDuration in the program,
+// including other packages. [FormatDurationWith] and the marshaling methods
+// of [Duration] are not affected by it.
+var DefaultSmallestUnit = time.Nanosecond
+
+// FormatDurationWith formats a duration like [FormatDuration], using the
+// provided options.
+func FormatDurationWith(d time.Duration, opts FormatOptions) string {
+	micro := "µs"
+	if opts.ASCII {
+		micro = "us"
+	}
+	if opts.SmallestUnit > time.Nanosecond {
+		t := d.Truncate(opts.SmallestUnit)
+		if t == 0 && d != 0 && opts.Floor != "" {
+			return opts.Floor
+		}
+		d = t
+	}
+	if d == 0 && len(opts.Units) == 0 && opts.Style == StyleCompact {
 		return "0s"
-	} else {
-		return formatHigh(d) + formatLow(d)
 	}
+	// the magnitude is computed as an unsigned value so that the minimum
+	// duration, which cannot be negated, is formatted correctly
+	v := uint64(d)
+	if d < 0 {
+		v = -v
+	}
+	var f string
+	switch {
+	case opts.Style == StyleISO8601:
+		f = formatISO8601(v)
+	case opts.Style == StyleClock:
+		f = formatClock(v)
+	case len(opts.Units) > 0:
+		f = formatFixed(v, opts.Units, opts.Separator)
+	default:
+		f = strings.Join(append(formatHigh(v), formatLow(v, micro)...), opts.Separator)
+	}
+	if opts.Grouping != "" && opts.Style == StyleCompact {
+		f = groupDigits(f, opts.Grouping)
+	}
+	if d >= 0 {
+		return f
+	}
+	switch opts.Negative {
+	case NegativeParenthesized:
+		return "-(" + f + ")"
+	case NegativeAgoSuffix:
+		return f + " ago"
+	default:
+		return "-" + f
+	}
+}
+
+// FormatISO8601Duration formats a duration as an ISO 8601 duration, such as
+// "PT1H30M" or "PT0.5S". Since this package treats a day as exactly 24 hours
+// and ISO 8601 days are calendar days, which vary in length, the largest
+// component is hours, so one and a half days is formatted as "PT36H". A zero
+// duration is formatted as "PT0S" and negative durations are formatted with a
+// leading "-", like "-PT1H".
+func FormatISO8601Duration(d time.Duration) string {
+	return FormatDurationWith(d, FormatOptions{Style: StyleISO8601})
+}
+
+// formatISO8601 formats the magnitude of a duration as an ISO 8601 duration.
+func formatISO8601(v uint64) string {
+	if v == 0 {
+		return "PT0S"
+	}
+	u := uint64(time.Hour)
+	f := "PT"
+	if h := v / u; h > 0 {
+		f += strconv.FormatUint(h, 10) + "H"
+	}
+	v %= u
+	u = uint64(time.Minute)
+	if m := v / u; m > 0 {
+		f += strconv.FormatUint(m, 10) + "M"
+	}
+	v %= u
+	if v > 0 {
+		f += formatSeconds(v) + "S"
+	}
+	return f
+}
+
+// formatClock formats the magnitude of a duration as "HH:MM:SS", where hours
+// may exceed two digits and any fraction of a second is appended.
+func formatClock(v uint64) string {
+	h := v / uint64(time.Hour)
+	m := v / uint64(time.Minute) % 60
+	s := formatSeconds(v % uint64(time.Minute))
+	if len(s) == 1 || s[1] == '.' {
+		s = "0" + s
+	}
+	return fmt.Sprintf("%02d:%02d:%s", h, m, s)
+}
+
+// formatSeconds formats a number of nanoseconds as decimal seconds, omitting
+// trailing zeros in the fraction.
+func formatSeconds(v uint64) string {
+	u := uint64(time.Second)
+	f := strconv.FormatUint(v/u, 10)
+	if n := v % u; n > 0 {
+		f += "." + strings.TrimRight(fmt.Sprintf("%09d", n), "0")
+	}
+	return f
+}
+
+// formatFixed formats the magnitude of a duration using exactly the provided
+// units, with each component zero-padded to the width of its unit.
+func formatFixed(v uint64, units []string, sep string) string {
+	f := make([]string, 0, len(units))
+	for _, e := range units {
+		u, ok := unitMap[e]
+		if !ok {
+			continue
+		}
+		f = append(f, fmt.Sprintf("%0*d%s", unitWidth(time.Duration(u)), v/u, e))
+		v %= u
+	}
+	return strings.Join(f, sep)
+}
+
+// groupDigits inserts sep between every three digits of the leading run of
+// digits in s, counting from the end of the run.
+func groupDigits(s, sep string) string {
+	n := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if n < 0 {
+		n = len(s)
+	}
+	if n <= 3 {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i > 0 && (n-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteString(s[n:])
+	return b.String()
+}
+
+// unitWidth returns the number of digits required to display the largest
+// count of a unit before it carries into the next larger unit.
+func unitWidth(u time.Duration) int {
+	for i := len(formatUnits) - 1; i > 0; i-- {
+		if formatUnits[i] == u {
+			return len(strconv.FormatInt(int64(formatUnits[i-1]/u-1), 10))
+		}
+	}
+	return 1 // days and weeks
 }
 
 func FormatSimplifiedDuration(d time.Duration) string {
@@ -322,3 +1041,181 @@ func FormatSimplifiedDuration(d time.Duration) string {
 		return fmt.Sprintf("%dns", d)
 	}
 }
+
+// unitNames maps the units this package formats to their suffixes.
+var unitNames = map[time.Duration]string{
+	time.Nanosecond:  "ns",
+	time.Microsecond: "µs",
+	time.Millisecond: "ms",
+	time.Second:      "s",
+	time.Minute:      "m",
+	time.Hour:        "h",
+	day:              "d",
+	week:             "w",
+}
+
+// formatUnits are the units used by FormatDuration, from largest to smallest.
+var formatUnits = []time.Duration{
+	day,
+	time.Hour,
+	time.Minute,
+	time.Second,
+	time.Millisecond,
+	time.Microsecond,
+	time.Nanosecond,
+}
+
+// FormatCountdown formats the time remaining on a countdown timer as "MM:SS",
+// or as "HH:MM:SS" when at least an hour remains, like "02:59:59". Partial
+// seconds are rounded up, so the display reads "00:00" only once no time
+// remains at all. A zero or negative remaining duration is formatted as
+// "00:00".
+func FormatCountdown(remaining time.Duration) string {
+	if remaining <= 0 {
+		return "00:00"
+	}
+	s := remaining / time.Second
+	if remaining%time.Second != 0 {
+		s++
+	}
+	if s >= 3600 {
+		return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+	} else {
+		return fmt.Sprintf("%02d:%02d", s/60, s%60)
+	}
+}
+
+// FormatTimeLeft is a convenience interface to [FormatTimeLeftRef] which
+// provides [Now] as the reference time.
+func FormatTimeLeft(deadline time.Time) string {
+	return FormatTimeLeftRef(deadline, Now())
+}
+
+// FormatTimeLeftRef describes the time remaining until a deadline, relative
+// to the provided reference time, like "2h left" before the deadline, or
+// "overdue by 5m" after it. At exactly the deadline, it is "due now". The
+// time remaining is formatted by [FormatSimplifiedDuration], so only its
+// largest components are displayed.
+func FormatTimeLeftRef(deadline, ref time.Time) string {
+	d := deadline.Sub(ref)
+	switch {
+	case d > 0:
+		return FormatSimplifiedDuration(d) + " left"
+	case d < 0:
+		if d == minDuration {
+			d = maxDuration
+		} else {
+			d = -d
+		}
+		return "overdue by " + FormatSimplifiedDuration(d)
+	default:
+		return "due now"
+	}
+}
+
+// A Pluralizer formats a count of a unit as one component of a long-form
+// duration, such as "2 minutes", in whatever grammar it implements. The unit
+// is one of the units used by [FormatDuration], from days to nanoseconds.
+type Pluralizer func(n uint64, unit time.Duration) string
+
+// longUnitNames are the English names of the units used by FormatDuration.
+var longUnitNames = map[time.Duration]string{
+	time.Nanosecond:  "nanosecond",
+	time.Microsecond: "microsecond",
+	time.Millisecond: "millisecond",
+	time.Second:      "second",
+	time.Minute:      "minute",
+	time.Hour:        "hour",
+	day:              "day",
+}
+
+// EnglishPluralizer is the default [Pluralizer], which formats components
+// like "1 minute" and "2 minutes".
+func EnglishPluralizer(n uint64, unit time.Duration) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, longUnitNames[unit])
+	} else {
+		return fmt.Sprintf("%d %ss", n, longUnitNames[unit])
+	}
+}
+
+// FormatDurationLong formats a duration as a comma-separated list of its
+// non-zero components in long form, like "1 day, 2 hours, 1 minute", with
+// each component formatted by the provided pluralizer. If the pluralizer is
+// nil, [EnglishPluralizer] is used. A zero duration is formatted as zero
+// seconds and negative durations are prefixed with "-".
+func FormatDurationLong(d time.Duration, p Pluralizer) string {
+	if p == nil {
+		p = EnglishPluralizer
+	}
+	if d == 0 {
+		return p(0, time.Second)
+	}
+	var sign string
+	v := uint64(d)
+	if d < 0 {
+		sign, v = "-", -v
+	}
+	var f []string
+	for _, u := range formatUnits {
+		if n := v / uint64(u); n != 0 {
+			f = append(f, p(n, u))
+		}
+		v %= uint64(u)
+	}
+	return sign + strings.Join(f, ", ")
+}
+
+// FormatDurationSig formats a duration as a decimal count of the largest unit
+// in which its magnitude is at least one, rounded to the provided number of
+// decimal places, with trailing zeros trimmed. For example, 36 hours is
+// formatted as "1.5d" and 3 hours 20 minutes as "3.3h" with one decimal
+// place. Negative durations are prefixed with "-".
+func FormatDurationSig(d time.Duration, decimals int) string {
+	if d == 0 {
+		return "0s"
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	var sign string
+	v := float64(d)
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	p := math.Pow10(decimals)
+	for _, u := range formatUnits {
+		r := math.Round(v/float64(u)*p) / p
+		if r >= 1 || u == time.Nanosecond {
+			f := strconv.FormatFloat(r, 'f', decimals, 64)
+			if strings.Contains(f, ".") {
+				f = strings.TrimRight(strings.TrimRight(f, "0"), ".")
+			}
+			return sign + f + unitNames[u]
+		}
+	}
+	panic("unreachable")
+}
+
+// FormatDurationAuto formats a duration in the single most natural unit with
+// at most one decimal place, like a humanized byte size, so 1500 milliseconds
+// is "1.5s", 90 seconds is "1.5m", and 36 hours is "1.5d". The decimal is
+// dropped when it is zero, so an hour is "1h". It is [FormatDurationSig] with
+// one decimal place.
+func FormatDurationAuto(d time.Duration) string {
+	return FormatDurationSig(d, 1)
+}
+
+// FormatDurationExact formats a duration as a decimal count of a single unit,
+// with trailing zeros trimmed. For example, 90 minutes is formatted as "1.5h"
+// when the unit is time.Hour and "90m" when the unit is time.Minute.
+//
+// The unit must be one of the units supported by [ParseDuration]; if it is
+// not, the duration is formatted with [FormatDuration] instead.
+func FormatDurationExact(d, unit time.Duration) string {
+	name, ok := unitNames[unit]
+	if !ok {
+		return FormatDuration(d)
+	}
+	return strconv.FormatFloat(float64(d)/float64(unit), 'f', -1, 64) + name
+}
@@ -1,6 +1,9 @@
 package timeutil
 
 import (
+	"encoding/xml"
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -25,3 +28,829 @@ func TestFormatDuration(t *testing.T) {
 	assert.Equal(t, "800ns", FormatDuration(time.Nanosecond*800))
 	assert.Equal(t, "1ns", FormatDuration(time.Nanosecond))
 }
+
+func TestFormatDurationInteriorZeros(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Expect   string
+	}{
+		{time.Hour + time.Second*5, "1h5s"},
+		{day + time.Second*5, "1d5s"},
+		{day + time.Minute, "1d1m"},
+		{day + time.Nanosecond, "1d1ns"},
+		{time.Hour + time.Millisecond, "1h1ms"},
+		{time.Second + time.Nanosecond, "1s1ns"},
+		{time.Millisecond + time.Nanosecond, "1ms1ns"},
+		{day*2 + time.Hour*3 + time.Millisecond*4, "2d3h4ms"},
+		{time.Minute + time.Microsecond, "1m1µs"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDuration(test.Duration), "#%d", i)
+		v, err := ParseDuration(test.Expect)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Duration, v, "#%d", i)
+		}
+	}
+}
+
+func TestDefaultSmallestUnit(t *testing.T) {
+	d := time.Second*3 + time.Millisecond*250 + time.Microsecond*7 + time.Nanosecond*9
+	assert.Equal(t, time.Nanosecond, DefaultSmallestUnit)
+	assert.Equal(t, "3s250ms7µs9ns", FormatDuration(d))
+
+	defer func(u time.Duration) { DefaultSmallestUnit = u }(DefaultSmallestUnit)
+	DefaultSmallestUnit = time.Millisecond
+	assert.Equal(t, "3s250ms", FormatDuration(d))
+	assert.Equal(t, "-3s250ms", FormatDuration(-d))
+	assert.Equal(t, "0s", FormatDuration(time.Microsecond*999))
+	assert.Equal(t, "3s250ms7µs9ns", FormatDurationWith(d, FormatOptions{}))
+
+	data, err := Duration(d).MarshalJSON()
+	if assert.NoError(t, err) {
+		assert.Equal(t, `"3s250ms7µs9ns"`, string(data))
+	}
+	assert.Equal(t, "3s250ms7µs9ns", (&DurationSlice{d}).String())
+}
+
+func TestFormatNegativeDuration(t *testing.T) {
+	assert.Equal(t, "-1d2h", FormatDuration(-(day + time.Hour*2)))
+	assert.Equal(t, "-1s500ms", FormatDuration(-time.Millisecond*1500))
+	assert.Equal(t, "-1ns", FormatDuration(-time.Nanosecond))
+	assert.Equal(t, "-1d 2h", FormatDurationWith(-(day+time.Hour*2), FormatOptions{Separator: " "}))
+	for i, e := range []time.Duration{-(day + time.Hour*2), -time.Millisecond * 1500, math.MaxInt64, math.MinInt64} {
+		v, err := ParseDuration(FormatDuration(e))
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, e, v, "#%d", i)
+		}
+	}
+}
+
+func TestFormatDurationNegativeStyle(t *testing.T) {
+	d := -time.Minute * 90
+	tests := []struct {
+		Duration time.Duration
+		Opts     FormatOptions
+		Expect   string
+	}{
+		{d, FormatOptions{}, "-1h30m"},
+		{d, FormatOptions{Negative: NegativeLeadingMinus}, "-1h30m"},
+		{d, FormatOptions{Negative: NegativeParenthesized}, "-(1h30m)"},
+		{d, FormatOptions{Negative: NegativeAgoSuffix}, "1h30m ago"},
+		{d, FormatOptions{Negative: NegativeAgoSuffix, Separator: " "}, "1h 30m ago"},
+		{d, FormatOptions{Negative: NegativeParenthesized, Units: []string{"h", "m"}}, "-(01h30m)"},
+		{-d, FormatOptions{Negative: NegativeAgoSuffix}, "1h30m"},
+		{-d, FormatOptions{Negative: NegativeParenthesized}, "1h30m"},
+		{0, FormatOptions{Negative: NegativeAgoSuffix}, "0s"},
+		{math.MinInt64, FormatOptions{Negative: NegativeAgoSuffix, SmallestUnit: day}, "106751d ago"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDurationWith(test.Duration, test.Opts), "#%d", i)
+	}
+}
+
+func TestFormatMinDuration(t *testing.T) {
+	d := time.Duration(math.MinInt64)
+	assert.Equal(t, "-106751d23h47m16s854ms775µs808ns", FormatDuration(d))
+	assert.Equal(t, "-106751d 23h 47m 16s 854ms 775us 808ns", FormatDurationWith(d, FormatOptions{ASCII: true, Separator: " "}))
+	assert.Equal(t, "-106751d23h47m16s", FormatDurationWith(d, FormatOptions{SmallestUnit: time.Second}))
+	assert.Equal(t, "-2562047h47m16s", FormatDurationWith(d, FormatOptions{Units: []string{"h", "m", "s"}}))
+	assert.Equal(t, "106751d23h47m16s854ms775µs807ns", FormatDuration(math.MaxInt64))
+	v, err := ParseDuration(FormatDuration(d))
+	if assert.NoError(t, err) {
+		assert.Equal(t, d, v)
+	}
+}
+
+func TestFormatDurationWith(t *testing.T) {
+	d := time.Second + time.Microsecond*8
+	assert.Equal(t, "1s8µs", FormatDurationWith(d, FormatOptions{}))
+	assert.Equal(t, "1s8us", FormatDurationWith(d, FormatOptions{ASCII: true}))
+	assert.Equal(t, FormatDuration(d), FormatDurationWith(d, FormatOptions{}))
+	assert.Equal(t, "3d2h5m", FormatDurationWith(day*3+time.Hour*2+time.Minute*5, FormatOptions{}))
+	assert.Equal(t, "3d 2h 5m", FormatDurationWith(day*3+time.Hour*2+time.Minute*5, FormatOptions{Separator: " "}))
+	assert.Equal(t, "1s 8µs", FormatDurationWith(d, FormatOptions{Separator: " "}))
+	assert.Equal(t, "8ms", FormatDurationWith(time.Millisecond*8, FormatOptions{Separator: " "}))
+	assert.Equal(t, "0s", FormatDurationWith(0, FormatOptions{Separator: " "}))
+	assert.Equal(t, "1s", FormatDurationWith(d, FormatOptions{SmallestUnit: time.Millisecond}))
+	assert.Equal(t, "1h2m", FormatDurationWith(time.Hour+time.Minute*2+time.Second*59, FormatOptions{SmallestUnit: time.Minute}))
+	for _, opts := range []FormatOptions{{}, {ASCII: true}} {
+		v, err := ParseDuration(FormatDurationWith(d, opts))
+		if assert.NoError(t, err) {
+			assert.Equal(t, d, v)
+		}
+	}
+}
+
+func TestFormatDurationUnits(t *testing.T) {
+	hms := FormatOptions{Units: []string{"h", "m", "s"}, Separator: " "}
+	tests := []struct {
+		Duration time.Duration
+		Opts     FormatOptions
+		Expect   string
+	}{
+		{0, hms, "00h 00m 00s"},
+		{time.Second * 90, hms, "00h 01m 30s"},
+		{time.Hour*9 + time.Minute*5 + time.Second*7 + time.Millisecond*999, hms, "09h 05m 07s"},
+		{time.Hour * 123, hms, "123h 00m 00s"},
+		{-time.Minute * 61, hms, "-01h 01m 00s"},
+		{day*2 + time.Hour*3, FormatOptions{Units: []string{"d", "h"}}, "2d03h"},
+		{time.Second + time.Millisecond*5, FormatOptions{Units: []string{"s", "ms"}}, "01s005ms"},
+		{time.Microsecond * 42, FormatOptions{Units: []string{"ms", "us", "ns"}, Separator: ":"}, "000ms:042us:000ns"},
+		{time.Second * 90, FormatOptions{Units: []string{"m", "x", "s"}}, "01m30s"},
+		{time.Millisecond * 500, FormatOptions{Units: []string{"s"}, SmallestUnit: time.Second, Floor: "<1s"}, "<1s"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDurationWith(test.Duration, test.Opts), "#%d", i)
+	}
+}
+
+func TestFormatDurationStyle(t *testing.T) {
+	d := day + time.Hour*2 + time.Minute*3 + time.Second*4 + time.Millisecond*500
+	tests := []struct {
+		Duration time.Duration
+		Opts     FormatOptions
+		Expect   string
+	}{
+		{d, FormatOptions{}, "1d2h3m4s500ms"},
+		{d, FormatOptions{Style: StyleCompact}, "1d2h3m4s500ms"},
+		{d, FormatOptions{Style: StyleISO8601}, "PT26H3M4.5S"},
+		{d, FormatOptions{Style: StyleClock}, "26:03:04.5"},
+		{d, FormatOptions{Style: StyleISO8601, SmallestUnit: time.Second}, "PT26H3M4S"},
+		{d, FormatOptions{Style: StyleClock, SmallestUnit: time.Second}, "26:03:04"},
+		{-d, FormatOptions{Style: StyleISO8601}, "-PT26H3M4.5S"},
+		{-d, FormatOptions{Style: StyleClock, Negative: NegativeAgoSuffix}, "26:03:04.5 ago"},
+		{0, FormatOptions{Style: StyleISO8601}, "PT0S"},
+		{0, FormatOptions{Style: StyleClock}, "00:00:00"},
+		{time.Minute * 90, FormatOptions{Style: StyleISO8601}, "PT1H30M"},
+		{time.Second * 5, FormatOptions{Style: StyleISO8601}, "PT5S"},
+		{time.Nanosecond, FormatOptions{Style: StyleISO8601}, "PT0.000000001S"},
+		{time.Second * 5, FormatOptions{Style: StyleClock}, "00:00:05"},
+		{time.Millisecond * 250, FormatOptions{Style: StyleClock}, "00:00:00.25"},
+		{time.Hour * 100, FormatOptions{Style: StyleClock}, "100:00:00"},
+		{math.MinInt64, FormatOptions{Style: StyleISO8601}, "-PT2562047H47M16.854775808S"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDurationWith(test.Duration, test.Opts), "#%d", i)
+	}
+	assert.Equal(t, "PT1H30M", FormatISO8601Duration(time.Minute*90))
+	assert.Equal(t, "-PT0.5S", FormatISO8601Duration(-time.Millisecond*500))
+}
+
+func TestFormatDurationGrouping(t *testing.T) {
+	d := day*12345 + time.Hour*6
+	tests := []struct {
+		Duration time.Duration
+		Opts     FormatOptions
+		Expect   string
+	}{
+		{d, FormatOptions{}, "12345d6h"},
+		{d, FormatOptions{Grouping: ","}, "12,345d6h"},
+		{d, FormatOptions{Grouping: " ", Separator: " "}, "12 345d 6h"},
+		{-d, FormatOptions{Grouping: ","}, "-12,345d6h"},
+		{-d, FormatOptions{Grouping: ",", Negative: NegativeParenthesized}, "-(12,345d6h)"},
+		{day * 366, FormatOptions{Grouping: ","}, "366d"},
+		{day * 1000, FormatOptions{Grouping: ","}, "1,000d"},
+		{time.Hour * 1234567, FormatOptions{Grouping: ",", Units: []string{"h", "m"}}, "1,234,567h00m"},
+		{time.Second * 1500, FormatOptions{Grouping: ",", Units: []string{"s"}}, "1,500s"},
+		{0, FormatOptions{Grouping: ","}, "0s"},
+		{math.MaxInt64, FormatOptions{Grouping: ",", SmallestUnit: time.Second}, "106,751d23h47m16s"},
+		{d, FormatOptions{Grouping: ",", Style: StyleClock}, "296286:00:00"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDurationWith(test.Duration, test.Opts), "#%d", i)
+	}
+}
+
+func TestFormatDurationLong(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Expect   string
+	}{
+		{0, "0 seconds"},
+		{time.Second, "1 second"},
+		{time.Minute, "1 minute"},
+		{time.Minute * 2, "2 minutes"},
+		{day + time.Hour*2 + time.Minute, "1 day, 2 hours, 1 minute"},
+		{time.Millisecond + time.Nanosecond*2, "1 millisecond, 2 nanoseconds"},
+		{-time.Hour * 25, "-1 day, 1 hour"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDurationLong(test.Duration, nil), "#%d", i)
+		assert.Equal(t, test.Expect, FormatDurationLong(test.Duration, EnglishPluralizer), "#%d", i)
+	}
+
+	// a pluralizer with a distinct form for two, like Slovenian's dual
+	dual := func(n uint64, unit time.Duration) string {
+		name := unitNames[unit]
+		switch n {
+		case 1:
+			return fmt.Sprintf("%d %s", n, name)
+		case 2:
+			return fmt.Sprintf("%d %s (dual)", n, name)
+		default:
+			return fmt.Sprintf("%d %s (plural)", n, name)
+		}
+	}
+	assert.Equal(t, "1 h, 2 m (dual), 3 s (plural)", FormatDurationLong(time.Hour+time.Minute*2+time.Second*3, dual))
+	assert.Equal(t, "0 s (plural)", FormatDurationLong(0, dual))
+}
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		Remaining time.Duration
+		Expect    string
+	}{
+		{0, "00:00"},
+		{-time.Minute, "00:00"},
+		{math.MinInt64, "00:00"},
+		{time.Nanosecond, "00:01"},
+		{time.Second * 59, "00:59"},
+		{time.Second*59 + time.Millisecond*500, "01:00"},
+		{time.Second * 60, "01:00"},
+		{time.Minute*59 + time.Second*59, "59:59"},
+		{time.Hour, "01:00:00"},
+		{time.Hour*3 - time.Second, "02:59:59"},
+		{time.Hour*3 - time.Millisecond, "03:00:00"},
+		{time.Hour * 100, "100:00:00"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatCountdown(test.Remaining), "#%d", i)
+	}
+}
+
+func TestFormatTimeLeft(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Deadline time.Time
+		Expect   string
+	}{
+		{ref.Add(time.Hour*2 + time.Minute*30), "2h left"},
+		{ref.Add(time.Minute * 5), "5m left"},
+		{ref.Add(day*2 + time.Hour*3), "2d 3h left"},
+		{ref.Add(time.Nanosecond), "1ns left"},
+		{ref.Add(-time.Minute * 5), "overdue by 5m"},
+		{ref.Add(-time.Second * 90), "overdue by 1m"},
+		{ref, "due now"},
+		{ref.In(mustLoadLocation(t, "Asia/Tokyo")), "due now"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatTimeLeftRef(test.Deadline, ref), "#%d", i)
+	}
+
+	defer func(f func() time.Time) { Now = f }(Now)
+	Now = func() time.Time { return ref }
+	assert.Equal(t, "5m left", FormatTimeLeft(ref.Add(time.Minute*5)))
+	assert.Equal(t, "due now", FormatTimeLeft(ref))
+}
+
+func TestFormatDurationAuto(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Expect   string
+	}{
+		{0, "0s"},
+		{time.Nanosecond * 5, "5ns"},
+		{time.Nanosecond * 1500, "1.5µs"},
+		{time.Microsecond * 250, "250µs"},
+		{time.Millisecond * 1500, "1.5s"},
+		{time.Millisecond * 1540, "1.5s"},
+		{time.Millisecond * 1560, "1.6s"},
+		{time.Second, "1s"},
+		{time.Second * 90, "1.5m"},
+		{time.Minute, "1m"},
+		{time.Hour, "1h"},
+		{time.Hour * 36, "1.5d"},
+		{day * 3, "3d"},
+		{day * 400, "400d"},
+		{time.Millisecond * 59970, "1m"}, // rounds up into the next unit
+		{-time.Second * 90, "-1.5m"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDurationAuto(test.Duration), "#%d", i)
+	}
+}
+
+func TestFormatDurationExact(t *testing.T) {
+	assert.Equal(t, "1.5h", FormatDurationExact(time.Minute*90, time.Hour))
+	assert.Equal(t, "90m", FormatDurationExact(time.Minute*90, time.Minute))
+	assert.Equal(t, "0.5d", FormatDurationExact(time.Hour*12, day))
+	assert.Equal(t, "2w", FormatDurationExact(day*14, week))
+	assert.Equal(t, "0s", FormatDurationExact(0, time.Second))
+	assert.Equal(t, "-1.25s", FormatDurationExact(-time.Millisecond*1250, time.Second))
+	assert.Equal(t, "1h30m", FormatDurationExact(time.Minute*90, time.Second*3)) // unsupported unit
+}
+
+func TestDurationCompare(t *testing.T) {
+	assert.True(t, Duration(time.Second).LessThan(Duration(time.Minute)))
+	assert.False(t, Duration(time.Minute).LessThan(Duration(time.Second)))
+	assert.False(t, Duration(time.Minute).LessThan(Duration(time.Minute)))
+	assert.True(t, Duration(time.Minute).GreaterThan(Duration(time.Second)))
+	assert.False(t, Duration(time.Second).GreaterThan(Duration(time.Minute)))
+	assert.False(t, Duration(time.Minute).GreaterThan(Duration(time.Minute)))
+	assert.True(t, Duration(time.Minute).Equal(Duration(time.Second*60)))
+	assert.False(t, Duration(time.Minute).Equal(Duration(time.Second)))
+	assert.Equal(t, Duration(time.Minute), Duration(-time.Minute).Abs())
+	assert.Equal(t, Duration(time.Minute), Duration(time.Minute).Abs())
+	assert.Equal(t, Duration(0), Duration(0).Abs())
+	assert.Equal(t, Duration(math.MaxInt64), Duration(math.MinInt64).Abs())
+}
+
+func TestParseDurations(t *testing.T) {
+	v, err := ParseDurations("1h,30m,2d", ",")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []time.Duration{time.Hour, time.Minute * 30, day * 2}, v)
+	}
+	v, err = ParseDurations(" 1h | 30m ", "|")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []time.Duration{time.Hour, time.Minute * 30}, v)
+	}
+	_, err = ParseDurations("1h,30x,2d", ",")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "index 1")
+	}
+	_, err = ParseDurations("1h,,2d", ",")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "empty duration at index 1")
+	}
+	_, err = ParseDurations("", ",")
+	assert.Error(t, err)
+}
+
+func TestParseDurationStdlib(t *testing.T) {
+	tests := []time.Duration{
+		0,
+		time.Nanosecond,
+		time.Microsecond * 1500,
+		time.Millisecond * 500,
+		time.Second + time.Millisecond*500,
+		time.Hour,
+		time.Hour * 26,
+		time.Hour*26 + time.Minute*3 + time.Second*4 + time.Nanosecond*5,
+		time.Hour * 9999,
+		-time.Minute * 90,
+		math.MaxInt64,
+		math.MinInt64,
+	}
+	for i, test := range tests {
+		v, err := ParseDuration(test.String())
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test, v, "#%d", i)
+		}
+	}
+	for _, s := range []string{"0s", "1h0m0s", "9999h0m0s", "26h0m0s"} {
+		expect, err := time.ParseDuration(s)
+		assert.NoError(t, err, s)
+		v, err := ParseDuration(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, expect, v, s)
+		}
+	}
+}
+
+func TestParseDurationSign(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect time.Duration
+		Err    bool
+	}{
+		{Expr: "0", Expect: 0},
+		{Expr: "-0", Expect: 0},
+		{Expr: "+0", Expect: 0},
+		{Expr: "-0s", Expect: 0},
+		{Expr: "+0s", Expect: 0},
+		{Expr: "+.5h", Expect: time.Minute * 30},
+		{Expr: "-.5h", Expect: -time.Minute * 30},
+		{Expr: "+1.s", Expect: time.Second},
+		{Expr: "-1h+1m", Err: true},
+		{Expr: "-", Err: true},
+		{Expr: "+", Err: true},
+		{Expr: "", Err: true},
+		{Expr: "--1s", Err: true},
+		{Expr: "+-1s", Err: true},
+		{Expr: "-.s", Err: true},
+		{Expr: "-0.0", Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseDuration(test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+		expect, stderr := time.ParseDuration(test.Expr)
+		assert.Equal(t, stderr != nil, err != nil, "#%d", i)
+		assert.Equal(t, expect, v, "#%d", i)
+	}
+}
+
+func TestParseDurationPrefix(t *testing.T) {
+	tests := []struct {
+		Expr      string
+		Expect    time.Duration
+		Remainder string
+		Err       bool
+	}{
+		{Expr: "1h30m", Expect: time.Minute * 90},
+		{Expr: "1h30m then do X", Expect: time.Minute * 90, Remainder: " then do X"},
+		{Expr: "-1.5s,2s", Expect: -time.Millisecond * 1500, Remainder: ",2s"},
+		{Expr: "10µs!", Expect: time.Microsecond * 10, Remainder: "!"},
+		{Expr: "2d5", Expect: day * 2, Remainder: "5"},
+		{Expr: "1h30mins", Expect: time.Hour, Remainder: "30mins"},
+		{Expr: "do X in 1h", Err: true},
+		{Expr: "1hour", Err: true},
+		{Expr: "5 minutes", Err: true},
+		{Expr: "-", Err: true},
+		{Expr: "", Err: true},
+		{Expr: ".s", Err: true},
+		{Expr: "9999999999999999999h", Err: true},
+	}
+	for i, test := range tests {
+		v, rem, err := ParseDurationPrefix(test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+			assert.Equal(t, test.Expr, rem, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+			assert.Equal(t, test.Remainder, rem, "#%d", i)
+		}
+	}
+}
+
+func TestParseDurationRange(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect time.Duration
+		Err    string
+	}{
+		{Expr: "1s", Expect: time.Second},
+		{Expr: "30m", Expect: time.Minute * 30},
+		{Expr: "1h", Expect: time.Hour},
+		{Expr: "500ms", Err: `time: duration "500ms" is less than the minimum of 1s`},
+		{Expr: "-1h", Err: `time: duration "-1h" is less than the minimum of 1s`},
+		{Expr: "1h0m1s", Err: `time: duration "1h0m1s" is greater than the maximum of 1h`},
+		{Expr: "1d", Err: `time: duration "1d" is greater than the maximum of 1h`},
+		{Expr: "1x", Err: `time: unknown unit "x" in duration "1x"`},
+	}
+	for i, test := range tests {
+		v, err := ParseDurationRange(test.Expr, time.Second, time.Hour)
+		if test.Err != "" {
+			if assert.Error(t, err, "#%d", i) {
+				assert.Equal(t, test.Err, err.Error(), "#%d", i)
+			}
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseDurationOverflow(t *testing.T) {
+	parsers := []func(string) (time.Duration, error){ParseDuration, ParseDurationStrict, ParseDurationHuman, ParseDurationLoose}
+	for _, e := range []string{"9223372036854775808ns", "99999999999999999999ns", "2562048h", "-2562048h", "2562047h47m16s854ms775µs808ns"} {
+		for i, parse := range parsers {
+			_, err := parse(e)
+			if assert.Error(t, err, "#%d: %s", i, e) {
+				assert.ErrorIs(t, err, ErrDurationOverflow, "#%d: %s", i, e)
+			}
+		}
+	}
+	_, err := ParseDuration("2562048h")
+	if assert.Error(t, err) {
+		assert.Equal(t, `time: value too large in duration "2562048h"`, err.Error())
+	}
+	_, err = ParseDurationLoose("1e20ns")
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+	_, err = ParseDurationHuman("2562048 hours")
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+	_, err = ParseDurations("1h,2562048h", ",")
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+
+	v, err := ParseDuration("-2562047h47m16s854ms775µs808ns")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(math.MinInt64), v)
+	}
+	for _, e := range []string{"1x", "1", "", ".s"} {
+		_, err := ParseDuration(e)
+		if assert.Error(t, err, e) {
+			assert.NotErrorIs(t, err, ErrDurationOverflow, e)
+		}
+	}
+}
+
+func TestParseDurationStrict(t *testing.T) {
+	v, err := ParseDurationStrict("1h30m")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Minute*90, v)
+	}
+	_, err = ParseDurationStrict("1d")
+	if assert.Error(t, err) {
+		assert.Equal(t, `time: approximate unit "d" not allowed in duration "1d"`, err.Error())
+	}
+	_, err = ParseDurationStrict("1h2w")
+	assert.Error(t, err)
+	v, err = ParseDuration("1d")
+	if assert.NoError(t, err) {
+		assert.Equal(t, day, v)
+	}
+}
+
+func TestParseDurationHuman(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect time.Duration
+		Err    bool
+	}{
+		{Expr: "1h", Expect: time.Hour},
+		{Expr: "1hr", Expect: time.Hour},
+		{Expr: "2hrs", Expect: time.Hour * 2},
+		{Expr: "1hour", Expect: time.Hour},
+		{Expr: "2 hours", Expect: time.Hour * 2},
+		{Expr: "1min", Expect: time.Minute},
+		{Expr: "2mins", Expect: time.Minute * 2},
+		{Expr: "1 minute", Expect: time.Minute},
+		{Expr: "2 minutes", Expect: time.Minute * 2},
+		{Expr: "1sec", Expect: time.Second},
+		{Expr: "2secs", Expect: time.Second * 2},
+		{Expr: "1 second", Expect: time.Second},
+		{Expr: "2 seconds", Expect: time.Second * 2},
+		{Expr: "1 day", Expect: day},
+		{Expr: "2days", Expect: day * 2},
+		{Expr: "1 week", Expect: week},
+		{Expr: "2 weeks", Expect: week * 2},
+		{Expr: "1 Hour 30 Mins", Expect: time.Minute * 90},
+		{Expr: "1.5 hours", Expect: time.Minute * 90},
+		{Expr: "- 1 hour", Expect: -time.Hour},
+		{Expr: "3d 2h 5m", Expect: day*3 + time.Hour*2 + time.Minute*5},
+		{Expr: "250ms", Expect: time.Millisecond * 250},
+		{Expr: "0", Expect: 0},
+		{Expr: "", Err: true},
+		{Expr: "1 fortnight", Err: true},
+		{Expr: "1", Err: true},
+		{Expr: "hours", Err: true},
+		{Expr: "1 hour and 30 minutes", Expect: time.Minute * 90},
+		{Expr: "1 Hour AND 30 Minutes", Expect: time.Minute * 90},
+		{Expr: "2 days, 3 hours", Expect: day*2 + time.Hour*3},
+		{Expr: "2 days,3 hours", Expect: day*2 + time.Hour*3},
+		{Expr: "1 day, 2 hours, and 5 minutes", Expect: day + time.Hour*2 + time.Minute*5},
+		{Expr: "1h and 30m", Expect: time.Minute * 90},
+		{Expr: "1 hour and", Err: true},
+		{Expr: "1 hour,", Err: true},
+		{Expr: "and 1 hour", Err: true},
+		{Expr: ", 1 hour", Err: true},
+		{Expr: "1 hour and and 5 minutes", Err: true},
+		{Expr: "1 hour andy 5 minutes", Err: true},
+		{Expr: "1, 5 minutes", Err: true},
+		{Expr: "1 30m", Err: true},
+		{Expr: "1 2 hours", Err: true},
+		{Expr: "1 5 minutes", Err: true},
+		{Expr: "0 5 minutes", Err: true},
+		{Expr: "- 0", Expect: 0},
+		{Expr: "~5m", Expect: time.Minute * 5},
+		{Expr: "~ 5 mins", Expect: time.Minute * 5},
+		{Expr: "about 2 hours", Expect: time.Hour * 2},
+		{Expr: "About 1 hour and 30 minutes", Expect: time.Minute * 90},
+		{Expr: "~-5m", Expect: -time.Minute * 5},
+		{Expr: "~", Err: true},
+		{Expr: "about", Err: true},
+		{Expr: "about2h", Err: true},
+		{Expr: "~~5m", Err: true},
+		{Expr: "5m~", Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseDurationHuman(test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	_, err := ParseDuration("1hour")
+	assert.Error(t, err)
+	_, err = ParseDuration("~5m")
+	assert.Error(t, err)
+	v, err := ParseDurationHuman(FormatDurationWith(day*3+time.Hour*2+time.Minute*5, FormatOptions{Separator: " "}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, day*3+time.Hour*2+time.Minute*5, v)
+	}
+}
+
+func TestDurationXML(t *testing.T) {
+	type entity struct {
+		XMLName  xml.Name `xml:"entity"`
+		Timeout  Duration `xml:"timeout,attr"`
+		Interval Duration `xml:"interval"`
+	}
+	e := entity{
+		Timeout:  Duration(time.Second * 30),
+		Interval: Duration(day + time.Hour*2),
+	}
+	data, err := xml.Marshal(e)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `<entity timeout="30s"><interval>1d2h</interval></entity>`, string(data))
+	}
+	var v entity
+	err = xml.Unmarshal(data, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, e.Timeout, v.Timeout)
+		assert.Equal(t, e.Interval, v.Interval)
+	}
+	err = xml.Unmarshal([]byte(`<entity timeout="30x"><interval>1d</interval></entity>`), &v)
+	assert.Error(t, err)
+	err = xml.Unmarshal([]byte(`<entity timeout="30s"><interval>1x</interval></entity>`), &v)
+	assert.Error(t, err)
+}
+
+func TestDurationBinary(t *testing.T) {
+	for i, e := range []Duration{0, Duration(time.Second * 30), Duration(-time.Hour), Duration(math.MaxInt64), Duration(math.MinInt64)} {
+		data, err := e.MarshalBinary()
+		if assert.NoError(t, err, "#%d", i) && assert.Len(t, data, 8, "#%d", i) {
+			var v Duration
+			err = v.UnmarshalBinary(data)
+			if assert.NoError(t, err, "#%d", i) {
+				assert.Equal(t, e, v, "#%d", i)
+			}
+		}
+	}
+	data, err := Duration(time.Second).MarshalBinary()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte{0, 0, 0, 0, 0x3b, 0x9a, 0xca, 0x00}, data)
+	}
+	v := Duration(time.Minute)
+	assert.Error(t, v.UnmarshalBinary(nil))
+	assert.Error(t, v.UnmarshalBinary(make([]byte, 7)))
+	assert.Error(t, v.UnmarshalBinary(make([]byte, 9)))
+	assert.Equal(t, Duration(time.Minute), v)
+}
+
+func TestFormatDurationFloor(t *testing.T) {
+	ms := FormatOptions{SmallestUnit: time.Millisecond, Floor: "<1ms"}
+	assert.Equal(t, "0s", FormatDurationWith(0, ms))
+	assert.Equal(t, "<1ms", FormatDurationWith(time.Microsecond*999, ms))
+	assert.Equal(t, "<1ms", FormatDurationWith(time.Nanosecond, ms))
+	assert.Equal(t, "1ms", FormatDurationWith(time.Millisecond, ms))
+	assert.Equal(t, "1ms", FormatDurationWith(time.Microsecond*1999, ms))
+	assert.Equal(t, "0s", FormatDurationWith(time.Microsecond*999, FormatOptions{SmallestUnit: time.Millisecond}))
+	assert.Equal(t, "1ns", FormatDurationWith(time.Nanosecond, FormatOptions{Floor: "<1ns"}))
+	assert.Equal(t, "0s", FormatDurationWith(0, FormatOptions{Floor: "<1ns"}))
+}
+
+func TestParseDurationLoose(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect time.Duration
+		Err    bool
+	}{
+		{Expr: "1e3ms", Expect: time.Second},
+		{Expr: "2.5e2s", Expect: time.Second * 250},
+		{Expr: "1E3ms", Expect: time.Second},
+		{Expr: "1e+3ms", Expect: time.Second},
+		{Expr: "5e-1s", Expect: time.Millisecond * 500},
+		{Expr: "0.05e1h", Expect: time.Minute * 30},
+		{Expr: "1e0h30m", Expect: time.Minute * 90},
+		{Expr: "-1e3ms", Expect: -time.Second},
+		{Expr: "0e99h", Expect: 0},
+		{Expr: "1e-99h", Expect: 0},
+		{Expr: "1h30m", Expect: time.Minute * 90},
+		{Expr: "1e30h", Err: true},
+		{Expr: "1e3000000000000000000000h", Err: true},
+		{Expr: "1e3h", Expect: time.Hour * 1000},
+		{Expr: "1e5w", Err: true},
+		{Expr: "1es", Err: true},
+		{Expr: "e3s", Err: true},
+		{Expr: "1e3", Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseDurationLoose(test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	_, err := ParseDuration("1e3ms")
+	assert.Error(t, err)
+	_, err = ParseDurationStrict("1e3ms")
+	assert.Error(t, err)
+}
+
+func TestParseDurationChecked(t *testing.T) {
+	tests := []struct {
+		Expr        string
+		Expect      time.Duration
+		Approximate bool
+		Err         bool
+	}{
+		{Expr: "1h30m", Expect: time.Minute * 90},
+		{Expr: "1d", Expect: day, Approximate: true},
+		{Expr: "2w", Expect: week * 2, Approximate: true},
+		{Expr: "1d12h", Expect: day + time.Hour*12, Approximate: true},
+		{Expr: "-1.5d", Expect: -(day + time.Hour*12), Approximate: true},
+		{Expr: "500ms", Expect: time.Millisecond * 500},
+		{Expr: "1ms1ns", Expect: time.Millisecond + time.Nanosecond},
+		{Expr: "0", Expect: 0},
+		{Expr: "1mo", Err: true},
+		{Expr: "1y", Err: true},
+		{Expr: "", Err: true},
+	}
+	for i, test := range tests {
+		v, approx, err := ParseDurationChecked(test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+			assert.False(t, approx, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+			assert.Equal(t, test.Approximate, approx, "#%d", i)
+			d, _ := ParseDuration(test.Expr)
+			assert.Equal(t, d, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseDurationUnicode(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Expect time.Duration
+		Err    bool
+	}{
+		{Expr: "1h30m", Expect: time.Minute * 90},
+		{Expr: "１０m", Expect: time.Minute * 10},         // fullwidth
+		{Expr: "١h٣٠m", Expect: time.Minute * 90},       // Arabic-Indic
+		{Expr: "۲.۵s", Expect: time.Millisecond * 2500}, // extended Arabic-Indic
+		{Expr: "-٥s", Expect: -time.Second * 5},
+		{Expr: "१२h", Expect: time.Hour * 12}, // Devanagari
+		{Expr: "１０ｍ", Err: true},              // fullwidth units are not normalized
+		{Expr: "٥", Err: true},
+		{Expr: "²s", Err: true}, // not a decimal digit
+		{Expr: "", Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseDurationUnicode(test.Expr)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	_, err := ParseDurationUnicode("٩٩٩٩٩٩٩٩٩٩٩h")
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+	_, err = ParseDurationUnicode("٥x")
+	assert.EqualError(t, err, "time: invalid duration "+quote("٥x"))
+	_, err = ParseDuration("１０m")
+	assert.Error(t, err)
+}
+
+func TestFormatDurationSig(t *testing.T) {
+	tests := []struct {
+		D        time.Duration
+		Decimals int
+		Expect   string
+	}{
+		{0, 1, "0s"},
+		{time.Hour * 36, 1, "1.5d"},
+		{time.Hour * 36, 0, "2d"},
+		{time.Hour*3 + time.Minute*20, 1, "3.3h"},
+		{time.Hour*3 + time.Minute*20, 2, "3.33h"},
+		{time.Hour*3 + time.Minute*20, 0, "3h"},
+		{time.Hour * 3, 2, "3h"},
+		{time.Minute * 90, 3, "1.5h"},
+		{time.Second*59 + time.Millisecond*970, 1, "1m"},
+		{time.Millisecond * 1500, 1, "1.5s"},
+		{time.Microsecond * 250, 1, "250µs"},
+		{time.Nanosecond * 5, 2, "5ns"},
+		{-time.Minute * 90, 1, "-1.5h"},
+		{time.Minute * 90, -1, "2h"},
+		{time.Duration(math.MinInt64), 2, "-106751.99d"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, FormatDurationSig(test.D, test.Decimals), "#%d", i)
+	}
+}
+
+func TestParseDurationAs(t *testing.T) {
+	tests := []struct {
+		Expr   string
+		Unit   time.Duration
+		Expect time.Duration
+		Err    bool
+	}{
+		{Expr: "90", Unit: time.Second, Expect: time.Second * 90},
+		{Expr: "1.5m", Unit: time.Second, Expect: time.Second * 90},
+		{Expr: "1.5", Unit: time.Minute, Expect: time.Second * 90},
+		{Expr: "90s", Unit: time.Minute, Expect: time.Second * 90},
+		{Expr: "-2", Unit: time.Hour, Expect: -time.Hour * 2},
+		{Expr: "+2", Unit: day, Expect: day * 2},
+		{Expr: "0", Unit: time.Hour, Expect: 0},
+		{Expr: "1h30", Unit: time.Minute, Err: true},
+		{Expr: "", Unit: time.Second, Err: true},
+		{Expr: "-", Unit: time.Second, Err: true},
+		{Expr: ".", Unit: time.Second, Err: true},
+		{Expr: "90", Unit: time.Second * 3, Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseDurationAs(test.Expr, test.Unit)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package timeutil
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FiscalYear returns the label of the fiscal year containing t, for fiscal
+// years beginning in the provided month. Fiscal years are labeled by the
+// calendar year in which they end. A zero month is treated as January.
+func FiscalYear(t time.Time, start time.Month) int {
+	if start > time.January && t.Month() >= start {
+		return t.Year() + 1
+	} else {
+		return t.Year()
+	}
+}
+
+// FiscalYearRange returns the range covered by the fiscal year with the
+// provided label, for fiscal years beginning in the provided month, in the
+// provided location. A zero month is treated as January.
+func FiscalYearRange(label int, start time.Month, loc *time.Location) TimeRange {
+	year := label
+	if start > time.January {
+		year--
+	} else {
+		start = time.January
+	}
+	t := time.Date(year, start, 1, 0, 0, 0, 0, loc)
+	return TimeRange{Start: t, End: t.AddDate(1, 0, 0)}
+}
+
+// parseFiscalYear parses a fiscal year expression, either an explicit label
+// like "fy2024" or a relative one like "last fiscal year".
+func parseFiscalYear(s string, ref time.Time, opts ExprOptions) (TimeRange, bool) {
+	v := strings.ToLower(s)
+	if len(v) == 6 && strings.HasPrefix(v, "fy") {
+		y, err := strconv.ParseUint(v[2:], 10, 16)
+		if err != nil {
+			return TimeRange{}, false
+		}
+		return FiscalYearRange(int(y), opts.FiscalYearStart, ref.Location()), true
+	}
+	f := strings.Fields(v)
+	if len(f) != 3 || f[1] != "fiscal" || f[2] != "year" {
+		return TimeRange{}, false
+	}
+	y := FiscalYear(ref, opts.FiscalYearStart)
+	switch f[0] {
+	case "this":
+	case "last":
+		y--
+	case "next":
+		y++
+	default:
+		return TimeRange{}, false
+	}
+	return FiscalYearRange(y, opts.FiscalYearStart, ref.Location()), true
+}
@@ -0,0 +1,77 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiscalYear(t *testing.T) {
+	assert.Equal(t, 2024, FiscalYear(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), time.April))
+	assert.Equal(t, 2025, FiscalYear(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), time.April))
+	assert.Equal(t, 2024, FiscalYear(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), time.January))
+	assert.Equal(t, 2024, FiscalYear(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), 0))
+}
+
+func TestParseFiscalYearExpr(t *testing.T) {
+	ref := time.Date(2025, 2, 14, 18, 17, 0, 0, time.UTC)
+	april := ExprOptions{FiscalYearStart: time.April}
+	tests := []struct {
+		Expr   string
+		Opts   ExprOptions
+		Expect TimeRange
+	}{
+		{
+			Expr: "fy2024",
+			Opts: april,
+			Expect: TimeRange{
+				Start: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "FY2024",
+			Expect: TimeRange{
+				Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "this fiscal year",
+			Opts: april,
+			Expect: TimeRange{
+				Start: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "last fiscal year",
+			Opts: april,
+			Expect: TimeRange{
+				Start: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "next fiscal year",
+			Opts: april,
+			Expect: TimeRange{
+				Start: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for i, test := range tests {
+		r, err := ParseRangeExprRefWith(test.Expr, ref, test.Opts)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, r, "#%d", i)
+		}
+		v, err := ParseExprRefWith(test.Expr, ref, test.Opts)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect.Start, v, "#%d", i)
+		}
+	}
+	_, err := ParseExprRefWith("fyXXXX", ref, april)
+	assert.Error(t, err)
+}
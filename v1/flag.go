@@ -0,0 +1,34 @@
+package timeutil
+
+import (
+	"strings"
+	"time"
+)
+
+// DurationSlice is a flag.Value which accumulates durations, so that a flag
+// may be repeated, as in "--interval 1s --interval 5s". Each value is parsed
+// with [ParseDuration].
+type DurationSlice []time.Duration
+
+// Set parses a duration and appends it to the slice.
+func (s *DurationSlice) Set(v string) error {
+	d, err := ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, d)
+	return nil
+}
+
+// String formats the durations like [FormatDuration] with full precision,
+// separated by commas. The result can be read back with [ParseDurations].
+func (s *DurationSlice) String() string {
+	if s == nil {
+		return ""
+	}
+	f := make([]string, len(*s))
+	for i, d := range *s {
+		f[i] = FormatDurationWith(d, FormatOptions{})
+	}
+	return strings.Join(f, ",")
+}
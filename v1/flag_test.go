@@ -0,0 +1,34 @@
+package timeutil
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationSlice(t *testing.T) {
+	var v DurationSlice
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&v, "interval", "Retry interval")
+
+	err := fs.Parse([]string{"-interval", "1s", "-interval", "5s", "-interval", "1d2h"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, DurationSlice{time.Second, time.Second * 5, day + time.Hour*2}, v)
+		assert.Equal(t, "1s,5s,1d2h", v.String())
+		d, err := ParseDurations(v.String(), ",")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []time.Duration(v), d)
+		}
+	}
+
+	err = fs.Parse([]string{"-interval", "5x"})
+	assert.Error(t, err)
+
+	var e DurationSlice
+	assert.Equal(t, "", e.String())
+	assert.Equal(t, "", (*DurationSlice)(nil).String())
+}
@@ -0,0 +1,28 @@
+package timeutil
+
+import (
+	"sync"
+	"time"
+)
+
+var locations sync.Map // map[string]*time.Location
+
+// LoadLocationCached returns the location with the provided name, like
+// time.LoadLocation, except that successfully loaded locations are cached
+// so that subsequent lookups of the same name are cheap. It is safe to call
+// concurrently. Failed lookups are not cached.
+func LoadLocationCached(name string) (*time.Location, error) {
+	return loadLocation(name)
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if v, ok := locations.Load(name); ok {
+		return v.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := locations.LoadOrStore(name, loc)
+	return v.(*time.Location), nil
+}
@@ -0,0 +1,31 @@
+package timeutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadLocationCached(t *testing.T) {
+	a, err := LoadLocationCached("Asia/Tokyo")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Asia/Tokyo", a.String())
+	}
+	b, err := LoadLocationCached("Asia/Tokyo")
+	if assert.NoError(t, err) {
+		assert.True(t, a == b, "Expected the cached location")
+	}
+	_, err = LoadLocationCached("Not/A_Zone")
+	assert.Error(t, err)
+	_, ok := locations.Load("Not/A_Zone")
+	assert.False(t, ok, "Expected failures not to be cached")
+}
+
+func BenchmarkLoadLocationCached(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, err := LoadLocationCached("America/New_York")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ParseWeekday parses the name of a day of the week, either in full, like
+// "Monday", or abbreviated to three letters, like "Mon". Case is ignored.
+func ParseWeekday(s string) (time.Weekday, error) {
+	if d, ok := parseWeekday(s); ok {
+		return d, nil
+	}
+	return 0, fmt.Errorf("Unknown weekday: %q", s)
+}
+
+// weekdayNames are the lower case names of the days of the week.
+var weekdayNames = [...]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// parseWeekday parses a weekday like [ParseWeekday], without the cost of
+// constructing an error when the input is not a weekday.
+func parseWeekday(s string) (time.Weekday, bool) {
+	v := strings.TrimSpace(s)
+	if len(v) < 3 || len(v) > len("wednesday") {
+		return 0, false
+	}
+	for i := 0; i < len(v); i++ {
+		if v[i] >= utf8.RuneSelf { // no weekday is spelled with other letters
+			return 0, false
+		}
+	}
+	for d, n := range weekdayNames {
+		if strings.EqualFold(v, n) || strings.EqualFold(v, n[:3]) {
+			return time.Weekday(d), true
+		}
+	}
+	return 0, false
+}
+
+// ParseMonth parses a month, either by name in full, like "January", or
+// abbreviated to three letters, like "Jan", ignoring case, or by number
+// from "1" or "01" through "12".
+func ParseMonth(s string) (time.Month, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.ParseUint(v, 10, 8); err == nil && len(v) <= 2 {
+		if n >= 1 && n <= 12 {
+			return time.Month(n), nil
+		}
+	} else if len(v) >= 3 {
+		for m := time.January; m <= time.December; m++ {
+			n := strings.ToLower(m.String())
+			if v == n || v == n[:3] {
+				return m, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("Unknown month: %q", s)
+}
@@ -0,0 +1,46 @@
+package timeutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeekday(t *testing.T) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		n := d.String()
+		for _, s := range []string{n, strings.ToLower(n), strings.ToUpper(n), n[:3], strings.ToLower(n[:3]), strings.ToUpper(n[:3]), " " + n + " "} {
+			v, err := ParseWeekday(s)
+			if assert.NoError(t, err, s) {
+				assert.Equal(t, d, v, s)
+			}
+		}
+	}
+	for _, s := range []string{"", "m", "mo", "mond", "fri day", "someday"} {
+		_, err := ParseWeekday(s)
+		if assert.Error(t, err, s) {
+			assert.Contains(t, err.Error(), `"`+s+`"`)
+		}
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	for m := time.January; m <= time.December; m++ {
+		n := m.String()
+		for _, s := range []string{n, strings.ToLower(n), strings.ToUpper(n), n[:3], strings.ToLower(n[:3]), strings.ToUpper(n[:3]), fmt.Sprint(int(m)), fmt.Sprintf("%02d", int(m))} {
+			v, err := ParseMonth(s)
+			if assert.NoError(t, err, s) {
+				assert.Equal(t, m, v, s)
+			}
+		}
+	}
+	for _, s := range []string{"", "0", "00", "13", "001", "-1", "ja", "janu", "smarch"} {
+		_, err := ParseMonth(s)
+		if assert.Error(t, err, s) {
+			assert.Contains(t, err.Error(), `"`+s+`"`)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package timeutil
+
+import (
+	"time"
+)
+
+// Now returns the current time. It is used by this package wherever the
+// current time is needed, and may be replaced, for example in tests, to fake
+// the clock. It is not safe to replace concurrently with its use.
+var Now = time.Now
+
+// Since returns the time elapsed since t, like time.Since, except that the
+// current time is obtained from [Now].
+//
+// Unlike time.Since, this cannot rely on the monotonic clock reading of a
+// time returned by time.Now unless [Now] is time.Now itself, so the result may
+// be affected by changes to the wall clock if Now has been replaced.
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Until returns the duration until t, like time.Until, except that the
+// current time is obtained from [Now]. The same monotonic clock caveat as
+// [Since] applies.
+func Until(t time.Time) time.Duration {
+	return t.Sub(Now())
+}
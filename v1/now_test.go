@@ -0,0 +1,24 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinceUntil(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	defer func(f func() time.Time) { Now = f }(Now)
+	Now = func() time.Time { return ref }
+
+	assert.Equal(t, time.Hour, Since(ref.Add(-time.Hour)))
+	assert.Equal(t, -time.Hour, Since(ref.Add(time.Hour)))
+	assert.Equal(t, time.Hour, Until(ref.Add(time.Hour)))
+	assert.Equal(t, -time.Hour, Until(ref.Add(-time.Hour)))
+
+	v, err := ParseExpr("-1h")
+	if assert.NoError(t, err) {
+		assert.Equal(t, ref.Add(-time.Hour), v)
+	}
+}
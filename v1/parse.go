@@ -2,11 +2,29 @@ package timeutil
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
-var errNoTimeSpecified = errors.New("No time specified")
+var (
+	errNoTimeSpecified  = errors.New("No time specified")
+	errUnrecognizedExpr = errors.New("Unrecognized expression")
+	errTrailingInput    = errors.New("Unexpected trailing input")
+	errInvalidLocation  = errors.New("Invalid location")
+)
+
+// ErrFutureNotAllowed and ErrPastNotAllowed are wrapped by the errors returned
+// when an expression refers to a time on the wrong side of the reference
+// time, as required by [ExprOptions.MustBePast] or
+// [ExprOptions.MustBeFuture], so that they can be distinguished from a syntax
+// error with errors.Is.
+var (
+	ErrFutureNotAllowed = errors.New("Future time not allowed")
+	ErrPastNotAllowed   = errors.New("Past time not allowed")
+)
 
 const (
 	formatDate      = "2006-01-02"
@@ -14,9 +32,139 @@ const (
 )
 
 // ParseExpr is a convenience interface to [ParseExprRef] which provides
-// [time.Now] as the reference time. It's usually the one you want.
+// [Now] as the reference time. It's usually the one you want.
 func ParseExpr(s string) (time.Time, error) {
-	return ParseExprRef(s, time.Now())
+	return ParseExprRef(s, Now())
+}
+
+// ExprKind describes the form of a time expression.
+type ExprKind int
+
+const (
+	ExprInvalid      ExprKind = iota // the expression could not be parsed
+	ExprConstant                     // a constant, like "now" or "eom"
+	ExprDay                          // a day constant, like "today"
+	ExprRelative                     // a relative offset, like "-10d"
+	ExprBusinessDays                 // a business day offset, like "in 3 business days"
+	ExprShortDate                    // a date without a year, like "11-14"
+	ExprDate                         // a date with a year, like "2024-11-14"
+	ExprRFC3339                      // an RFC 3339 timestamp
+	ExprFiscalYear                   // a fiscal year, like "fy2024"
+	ExprEpoch                        // seconds since the Unix epoch, like "@1699999999"
+	ExprWeekday                      // a day of the week, like "monday"
+	ExprTimeOfDay                    // a time of day, like "9am"
+	ExprCompact                      // a compact timestamp without a zone, like "20241114T1800"
+)
+
+var exprKindNames = []string{
+	ExprInvalid:      "invalid",
+	ExprConstant:     "constant",
+	ExprDay:          "day",
+	ExprRelative:     "relative",
+	ExprBusinessDays: "business-days",
+	ExprShortDate:    "short-date",
+	ExprDate:         "date",
+	ExprRFC3339:      "rfc3339",
+	ExprFiscalYear:   "fiscal-year",
+	ExprEpoch:        "epoch",
+	ExprWeekday:      "weekday",
+	ExprTimeOfDay:    "time-of-day",
+	ExprCompact:      "compact",
+}
+
+func (k ExprKind) String() string {
+	if k < 0 || int(k) >= len(exprKindNames) {
+		return fmt.Sprintf("ExprKind(%d)", int(k))
+	}
+	return exprKindNames[k]
+}
+
+// ExprOptions configures how time expressions are parsed.
+type ExprOptions struct {
+	// Holidays is the set of dates which are not considered business days when
+	// evaluating business day expressions, in addition to weekends.
+	Holidays map[Date]bool
+	// WeekStart is the day on which weeks begin when evaluating week
+	// expressions. If it is nil, weeks begin on [DefaultWeekStart].
+	WeekStart *time.Weekday
+	// MidWeek is the day of the week referred to by "mid-week". If it is nil,
+	// mid-week is Wednesday. The day is taken within the reference week as
+	// determined by WeekStart, so when weeks begin on Thursday, the default
+	// mid-week is the last day of the week.
+	MidWeek *time.Weekday
+	// FiscalYearStart is the month in which fiscal years begin when evaluating
+	// fiscal year expressions. If it is zero, fiscal years begin in January
+	// and coincide with calendar years.
+	FiscalYearStart time.Month
+	// DateOrder enables numeric dates with the year last, like "05/01/21",
+	// and determines the order of their day and month. They are ambiguous, so
+	// they are not accepted unless a date order is set.
+	DateOrder DateOrder
+	// Strict restricts timestamps which do not match any other form to
+	// RFC 3339 timestamps in exactly the form "2006-01-02T15:04:05Z07:00",
+	// with an optional fraction of a second, and reports any input which
+	// cannot be parsed as an unrecognized expression rather than returning
+	// the underlying parse error.
+	Strict bool
+	// Constants maps additional named constants, like "launch", to the times
+	// they refer to. They are matched exactly, after surrounding whitespace
+	// is trimmed, and are consulted before any built-in form, so they may
+	// shadow built-in constants like "now".
+	Constants map[string]time.Time
+	// MustBePast rejects expressions which refer to a time after the
+	// reference time. The reference time itself is permitted.
+	MustBePast bool
+	// MustBeFuture rejects expressions which refer to a time before the
+	// reference time. The reference time itself is permitted.
+	MustBeFuture bool
+	// DayParts maps the names of parts of the day, like "morning", to the
+	// times of day they refer to in expressions like "tomorrow morning". Names
+	// are matched in lower case. If it is nil, the default parts are used:
+	// morning at 09:00, afternoon at 13:00, evening at 18:00, and night at
+	// 21:00.
+	DayParts map[string]Clock
+}
+
+// defaultDayParts are the parts of the day used when none are configured.
+var defaultDayParts = map[string]Clock{
+	"morning":   {Hour: 9},
+	"afternoon": {Hour: 13},
+	"evening":   {Hour: 18},
+	"night":     {Hour: 21},
+}
+
+func (o ExprOptions) dayParts() map[string]Clock {
+	if o.DayParts != nil {
+		return o.DayParts
+	} else {
+		return defaultDayParts
+	}
+}
+
+// DateOrder is the order of the day and month in a numeric date with the year
+// last, like "05/01/21".
+type DateOrder int
+
+const (
+	DateOrderNone DateOrder = iota // numeric dates with the year last are not accepted
+	DateOrderMDY                   // month, day, year, like "05/01/21" for May 1st
+	DateOrderDMY                   // day, month, year, like "01/05/21" for May 1st
+)
+
+func (o ExprOptions) weekStart() time.Weekday {
+	if o.WeekStart != nil {
+		return *o.WeekStart
+	} else {
+		return DefaultWeekStart
+	}
+}
+
+func (o ExprOptions) midWeek() time.Weekday {
+	if o.MidWeek != nil {
+		return *o.MidWeek
+	} else {
+		return time.Wednesday
+	}
 }
 
 // ParseExprRef parses a time expression and returns the point in time that
@@ -26,62 +174,994 @@ func ParseExpr(s string) (time.Time, error) {
 // This function supports a variety of inputs:
 //
 //   - The special constants: "today", "yesterday", and "tomorrow", which refers
-//     to midnight on those days, relative to the reference time;
+//     to midnight on those days, relative to the reference time, in the
+//     reference time's location;
 //
 //   - The special constant: "now", which refers to the reference time, which
 //     is simply returned;
 //
+//   - The name of a day of the week, as accepted by [ParseWeekday], which
+//     refers to midnight on the next occurrence of that day after the
+//     reference day. If the reference time is on that day, it refers to the
+//     same day in the following week;
+//
+//   - A time of day, as accepted by [ParseClock], like "9am" or "17:30",
+//     which refers to that time on the reference day, in the reference
+//     time's location;
+//
+//   - An English time-of-day phrase, like "half past 9", "quarter past noon",
+//     "quarter to 5", or "3 o'clock", which refers to that time on the
+//     reference day. Since such hours don't say whether they are AM or PM,
+//     the next occurrence at or after the reference time is assumed, unless
+//     both have passed on the reference day, in which case the PM one is
+//     used;
+//
+//   - A time of day expressed as seconds since midnight with the prefix
+//     "tod:", like "tod:34200", which refers to that wall-clock time on the
+//     reference day, as computed by [TimeFromMidnight];
+//
+//   - A day followed by a part of the day, like "tomorrow morning", "this
+//     evening", or "friday afternoon", where the day is "this", "today",
+//     "tomorrow", "yesterday", or the name of a day of the week, and
+//     "tonight", which refers to this night. Unless configured otherwise via
+//     [ParseExprRefWith], the morning is 09:00, the afternoon is 13:00, the
+//     evening is 18:00, and the night is 21:00, in the reference time's
+//     location;
+//
+//   - The name of a day of the week followed by a time of day, like
+//     "monday 9am", which refers to that time on the day the weekday refers
+//     to;
+//
+//   - An expression which refers to a whole day, like "yesterday" or
+//     "2024-11-14", followed by a time of day, like "yesterday 5pm", which
+//     refers to that time on that day, in the day's location. In this and the
+//     weekday form, the time may be joined with "at", like "tomorrow at
+//     09:30" or "monday at 9am";
+//
+//   - The phrases "end of (day|week|month|year)" and their abbreviations
+//     "eod", "eow", "eom", and "eoy", in any case, which refer to the last
+//     instant of the current period in the reference time's location. Weeks
+//     begin on [DefaultWeekStart] unless configured otherwise via
+//     [ParseExprRefWith];
+//
+//   - The markers "mid-month" and "mid-week", in any case, which refer to
+//     midnight on the 15th of the reference month and on the mid-week day of
+//     the reference week, respectively, in the reference time's location.
+//     Mid-week is Wednesday unless configured otherwise via
+//     [ParseExprRefWith];
+//
+//   - An ordinal day of the reference month, like "15th" or "15th of the
+//     month", or the phrases "first of month" and "last day of month", which
+//     refer to midnight on that day in the reference time's location. An
+//     ordinal beyond the end of the month, like "31st" in February, is
+//     clamped to the last day of the month;
+//
 //   - A relative time adjustment, in the form: "(+|-)duration", where
 //     "duration" is a duration (as implemented in this package) relative to the
 //     reference time. For example, the expression "-10d" refers to the point in
-//     time 10 days ago at the same time as this function is invoked;
+//     time 10 days ago at the same time as this function is invoked. Terms
+//     with differing signs may be combined, so "+1d-2h" refers to the point
+//     in time 22 hours after the reference time. The duration may also be
+//     written with spaces and unit words, as accepted by [ParseDurationHuman],
+//     so "+3 days" and "- 2 weeks" are relative adjustments too;
 //
 //   - A date expressed as the day and month, which is assumed to be in the
 //     reference year; for example "11-14" refers to midnight on November 14th of
 //     the year of the reference time;
 //
 //   - A date expressed as the day, month, and year without a time, which
-//     refers to midnight on that date.
+//     refers to midnight on that date;
+//
+//   - Any other expression followed by whitespace and a relative time
+//     adjustment, which refers to that point in time adjusted by the offset.
+//     For example, "2021-05-01 +3d" refers to midnight on May 4th, 2021, and
+//     "today +2h" refers to 2AM on the reference day;
+//
+//   - Either form of date followed by a space and a timezone, which refers to
+//     midnight on that date in that zone. The zone may be a numeric offset
+//     like "-0500", "-05:00", or "-05", or one of "UTC", "GMT", or "Z". Other
+//     abbreviations like "EST" are ambiguous and are not supported. Dates
+//     without a zone are interpreted in UTC;
+//
+//   - Any other expression followed by "in" and the name of a location from
+//     the IANA time zone database, like "now in Asia/Tokyo", which refers to
+//     the same point in time as the expression, expressed in that location.
+//     The expression is evaluated relative to the reference time in that
+//     location, so "today in Europe/London" refers to midnight in London.
+//     Only names which contain a "/", or "UTC", are taken to be locations,
+//     and an unknown location among them is an error. The name "Local" is not
+//     supported, since it depends on the environment;
 //
-// Any other input, including an empty string is an error.
+//   - A business day offset, in the form "in N business days" or "N business
+//     days ago", which refers to the same time as the reference time, N
+//     business days later or earlier. Saturdays and Sundays are skipped, as
+//     are any holidays provided via [ParseExprRefWith];
+//
+//   - A fiscal year, in the form "fyYYYY" or "(this|last|next) fiscal year",
+//     which refers to midnight at the start of that fiscal year in the
+//     reference time's location. See [ParseExprRefWith] for how fiscal years
+//     are configured and labeled;
+//
+//   - A Unix timestamp, in the form "@seconds", like GNU date, where seconds
+//     is the number of seconds since the Unix epoch with an optional fraction
+//     of up to nine digits, for example "@1699999999.5". The result is in UTC;
+//
+//   - An RFC 3339 timestamp, like "2024-11-14T18:00:00Z", with an optional
+//     fraction of a second of up to nine digits, like
+//     "2024-11-14T18:17:00.5Z". The result keeps the timestamp's offset from
+//     UTC rather than being converted to UTC;
+//
+//   - A compact timestamp or date without a zone, in one of the layouts
+//     "2006-01-02T15:04", "2006-01-02T1504", "20060102T150405",
+//     "20060102T1504", or "20060102", for example "2024-11-14T18:00" or
+//     "20241114". The result is in UTC.
+//
+// Any other input, including an empty string is an error. Input following an
+// otherwise valid expression is not ignored: unless it is one of the
+// combinations described above, like a trailing offset or zone, it is an
+// error which identifies the unexpected input.
 func ParseExprRef(s string, ref time.Time) (time.Time, error) {
+	return ParseExprRefWith(s, ref, ExprOptions{})
+}
+
+// ParseExprRefWith parses a time expression like [ParseExprRef], using the
+// provided options.
+//
+// Numeric dates with the year last, like "05/01/21" or "05-01-2021", are
+// accepted when [ExprOptions.DateOrder] is set, which determines whether the
+// day or month comes first. The components may be separated by either slashes
+// or dashes. Two-digit years are expanded using a pivot: 00-68 refer to 2000
+// through 2068, and 69-99 refer to 1969 through 1999. They refer to midnight
+// on that date in UTC.
+//
+// Fiscal years begin in the month given by [ExprOptions.FiscalYearStart] and
+// are labeled by the calendar year in which they end. For example, when
+// fiscal years begin in April, "fy2024" runs from April 1, 2023 through
+// March 31, 2024. When fiscal years begin in January, they are labeled by
+// the calendar year they coincide with.
+//
+// When [ExprOptions.MustBePast] or [ExprOptions.MustBeFuture] is set, the
+// time an expression refers to is validated against the reference time, and
+// an error wrapping [ErrFutureNotAllowed] or [ErrPastNotAllowed] is returned
+// if it falls on the wrong side of it.
+func ParseExprRefWith(s string, ref time.Time, opts ExprOptions) (time.Time, error) {
+	t, _, err := parseExpr(s, ref, opts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if opts.MustBePast && t.After(ref) {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrFutureNotAllowed, strings.TrimSpace(s))
+	}
+	if opts.MustBeFuture && t.Before(ref) {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrPastNotAllowed, strings.TrimSpace(s))
+	}
+	return t, nil
+}
+
+// Bound identifies which end of a range a time expression is used for.
+type Bound int
+
+const (
+	BoundStart Bound = iota // the inclusive low bound of a range
+	BoundEnd                // the inclusive high bound of a range
+)
+
+// ParseExprRefBound parses a time expression like [ParseExprRef] for use as
+// one bound of an inclusive range. Expressions which refer to a whole day,
+// like "today", "friday" or "2024-11-14", are snapped to the start of that day for
+// [BoundStart] and to the last instant of that day for [BoundEnd], so that
+// the range includes the entire day. Likewise, fiscal years like "fy2024" are
+// snapped to the last instant of the fiscal year for BoundEnd. Other
+// expressions are unaffected.
+func ParseExprRefBound(s string, ref time.Time, which Bound) (time.Time, error) {
+	t, k, err := parseExpr(s, ref, ExprOptions{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch k {
+	case ExprDay, ExprWeekday, ExprShortDate, ExprDate:
+		if which == BoundEnd {
+			return EndOfDay(t), nil
+		} else {
+			return StartOfDay(t), nil
+		}
+	case ExprFiscalYear:
+		if which == BoundEnd {
+			// t is the start of the fiscal year, which always spans one year;
+			// see FiscalYearRange
+			return t.AddDate(1, 0, 0).Add(-time.Nanosecond), nil
+		} else {
+			return t, nil
+		}
+	default:
+		return t, nil
+	}
+}
+
+// ParseExprRefKind parses a time expression like [ParseExprRef] and also
+// reports which form of expression was matched. If the expression cannot be
+// parsed, the kind is [ExprInvalid].
+func ParseExprRefKind(s string, ref time.Time) (time.Time, ExprKind, error) {
+	return parseExpr(s, ref, ExprOptions{})
+}
+
+// ParseExprDelta parses a time expression like [ParseExprRef] and returns the
+// duration from the reference time to the time it refers to, which is
+// negative for times in the past. For example, "-10d" is -240 hours and
+// "tomorrow" is the duration until midnight at the start of the next day. As
+// with time.Time.Sub, a delta which does not fit in a duration is clamped to
+// the maximum or minimum duration.
+func ParseExprDelta(s string, ref time.Time) (time.Duration, error) {
+	t, err := ParseExprRef(s, ref)
+	if err != nil {
+		return 0, err
+	}
+	return t.Sub(ref), nil
+}
+
+// ParseExprAll parses each of a list of time expressions like [ParseExprRef],
+// relative to the same reference time, and collects the results rather than
+// stopping at the first failure. The returned slices are aligned with the
+// input: for each index, either the time is set and the error is nil, or the
+// time is zero and the error describes why that expression failed.
+func ParseExprAll(exprs []string, ref time.Time) ([]time.Time, []error) {
+	times := make([]time.Time, len(exprs))
+	errs := make([]error, len(exprs))
+	for i, e := range exprs {
+		times[i], errs[i] = ParseExprRef(e, ref)
+	}
+	return times, errs
+}
+
+func parseExpr(s string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, error) {
+	t, k, err := parseExprForm(s, ref, opts)
+	if err == nil {
+		return t, k, nil
+	}
+	if opts.Strict && !errors.Is(err, errNoTimeSpecified) && !errors.Is(err, errUnrecognizedExpr) {
+		return time.Time{}, ExprInvalid, fmt.Errorf("%w: %q", errUnrecognizedExpr, strings.TrimSpace(s))
+	}
+	if !opts.Strict && !errors.Is(err, errInvalidLocation) {
+		if terr := trailingInput(s, ref, opts); terr != nil {
+			return time.Time{}, ExprInvalid, terr
+		}
+	}
+	return time.Time{}, ExprInvalid, err
+}
+
+// trailingInput explains why an expression which could not be parsed failed
+// if it is a valid expression followed by unexpected input, like
+// "2021-05-01 extra", by returning an error which identifies the trailing
+// input. The longest valid leading expression is used. If there is none, it
+// returns nil and the original error should be reported.
+func trailingInput(s string, ref time.Time, opts ExprOptions) error {
+	v := strings.TrimSpace(s)
+	for i := strings.LastIndexFunc(v, unicode.IsSpace); i > 0; i = strings.LastIndexFunc(v[:i], unicode.IsSpace) {
+		a := strings.TrimSpace(v[:i])
+		if a == "" {
+			break
+		}
+		if _, _, err := parseExprForm(a, ref, opts); err == nil {
+			return fmt.Errorf("%w: %q after %q", errTrailingInput, strings.TrimSpace(v[i:]), a)
+		}
+	}
+	return nil
+}
+
+func parseExprForm(s string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, error) {
 	v := strings.TrimSpace(s)
 	if v == "" {
-		return time.Time{}, errNoTimeSpecified
+		return time.Time{}, ExprInvalid, errNoTimeSpecified
+	}
+	for _, m := range exprMatchers {
+		if t, k, ok, err := m(v, ref, opts); ok {
+			return t, k, err
+		}
+	}
+	return matchTimestamp(v, opts)
+}
+
+// An exprMatcher attempts to parse a trimmed, non-empty expression in one
+// form. If the expression is not in that form, ok is false and the next form
+// is attempted; otherwise the result, or the error, is final. Matchers should
+// reject input which cannot be in their form as cheaply as possible, since
+// every expression is offered to each of them in turn until one matches.
+type exprMatcher func(v string, ref time.Time, opts ExprOptions) (t time.Time, k ExprKind, ok bool, err error)
+
+// exprMatchers are the forms of expression, in the order they are attempted.
+// Expressions which match none of them are parsed as timestamps. It is
+// populated by init, since some forms are compositions which parse their
+// components recursively.
+var exprMatchers []exprMatcher
+
+func init() {
+	exprMatchers = []exprMatcher{
+		matchConstants,       // configured constants, which shadow everything else
+		matchLocation,        // "now in Asia/Tokyo"
+		matchBuiltin,         // "today", "now"
+		matchEndOf,           // "eom", "end of week"
+		matchMid,             // "mid-month"
+		matchOrdinal,         // "15th of the month"
+		matchWeekday,         // "monday"
+		matchClock,           // "9am", "17:30"
+		matchClockPhrase,     // "half past 9"
+		matchMidnightSeconds, // "tod:34200"
+		matchDayPart,         // "tomorrow morning"
+		matchDayClock,        // "monday 9am", "tomorrow at 5pm"
+		matchEpoch,           // "@1699999999"
+		matchBusinessDays,    // "in 3 business days"
+		matchFiscalYear,      // "fy2024"
+		matchTrailingOffset,  // "2021-05-01 +3d"
+		matchOffset,          // "-10d", "+3 days"
+		matchOrderedDate,     // "05/01/21"
+		matchZonedDate,       // "2021-05-01 -0500"
+		matchDate,            // "2021-05-01", "05-01"
 	}
-	switch v { // constants
+}
+
+func matchConstants(v string, _ time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	t, ok := opts.Constants[v]
+	return t, ExprConstant, ok, nil
+}
+
+func matchLocation(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	a, name, ok := splitLocation(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	loc, err := loadLocation(name)
+	if err != nil {
+		return time.Time{}, ExprInvalid, true, fmt.Errorf("%w %q: %w", errInvalidLocation, name, err)
+	}
+	t, k, err := parseExprForm(a, ref.In(loc), opts)
+	if err != nil {
+		return time.Time{}, ExprInvalid, true, err
+	}
+	return t.In(loc), k, true, nil
+}
+
+func matchBuiltin(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	switch v {
 	case "today":
-		return ref.Truncate(time.Hour * 24), nil
+		return StartOfDay(ref), ExprDay, true, nil
 	case "yesterday":
-		return ref.Truncate(time.Hour*24).AddDate(0, 0, -1), nil
+		return StartOfDay(ref).AddDate(0, 0, -1), ExprDay, true, nil
 	case "tomorrow":
-		return ref.Truncate(time.Hour*24).AddDate(0, 0, 1), nil
+		return StartOfDay(ref).AddDate(0, 0, 1), ExprDay, true, nil
 	case "now":
-		return ref, nil
+		return ref, ExprConstant, true, nil
+	default:
+		return time.Time{}, ExprInvalid, false, nil
 	}
-	if f := v[0]; f == '+' || f == '-' { // time must have at least 1 index since it's not ""
-		d, err := ParseDuration(v)
-		if err != nil {
-			return time.Time{}, err
+}
+
+func matchEndOf(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	t, ok := parseEndOf(v, ref, opts)
+	return t, ExprConstant, ok, nil
+}
+
+func matchMid(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	t, ok := parseMid(v, ref, opts)
+	return t, ExprDay, ok, nil
+}
+
+func matchOrdinal(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	t, ok := parseOrdinal(v, ref)
+	return t, ExprDay, ok, nil
+}
+
+func matchWeekday(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	d, ok := parseWeekday(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	return nextWeekday(ref, d), ExprWeekday, true, nil
+}
+
+func matchClock(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	c, ok := parseClock(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	return c.On(ref), ExprTimeOfDay, true, nil
+}
+
+func matchClockPhrase(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	t, ok := parseClockPhrase(v, ref)
+	return t, ExprTimeOfDay, ok, nil
+}
+
+func matchMidnightSeconds(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	n, ok := parseMidnightSeconds(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	return TimeFromMidnight(ref, n), ExprTimeOfDay, true, nil
+}
+
+func matchDayPart(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	t, k, ok := parseDayPart(v, ref, opts)
+	return t, k, ok, nil
+}
+
+func matchDayClock(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	a, c, ok := splitClock(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	if d, ok := parseWeekday(a); ok {
+		return c.On(nextWeekday(ref, d)), ExprTimeOfDay, true, nil
+	}
+	if t, k, err := parseExprForm(a, ref, opts); err == nil {
+		switch k {
+		case ExprDay, ExprShortDate, ExprDate:
+			return c.On(t), ExprTimeOfDay, true, nil
 		}
-		return ref.Add(d), nil
-	} else if len(v) == len(formatShortDate) {
-		t, err := time.Parse(formatDate, ref.Format("2006")+"-"+v) // assume current year
-		if err != nil {
-			return time.Time{}, err
+	}
+	return time.Time{}, ExprInvalid, false, nil
+}
+
+func matchEpoch(v string, _ time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	if v[0] != '@' {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	t, err := parseEpoch(v[1:])
+	if err != nil {
+		return time.Time{}, ExprInvalid, true, err
+	}
+	return t, ExprEpoch, true, nil
+}
+
+func matchBusinessDays(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	n, ok := parseBusinessDays(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	return AddBusinessDays(ref, n, opts.Holidays), ExprBusinessDays, true, nil
+}
+
+func matchFiscalYear(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	r, ok := parseFiscalYear(v, ref, opts)
+	return r.Start, ExprFiscalYear, ok, nil
+}
+
+func matchTrailingOffset(v string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	a, o, ok := splitOffset(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	t, _, err := parseExprForm(a, ref, opts)
+	if err != nil {
+		return time.Time{}, ExprInvalid, true, err
+	}
+	return t.Add(o), ExprRelative, true, nil
+}
+
+func matchOffset(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	if v[0] != '+' && v[0] != '-' {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	d, err := parseOffset(v)
+	if err != nil {
+		h, herr := ParseDurationHuman(v)
+		if herr != nil {
+			return time.Time{}, ExprInvalid, true, err
 		}
-		return t, nil
-	} else if len(v) == len(formatDate) {
-		t, err := time.Parse(formatDate, v)
+		d = h
+	}
+	return ref.Add(d), ExprRelative, true, nil
+}
+
+func matchOrderedDate(v string, _ time.Time, opts ExprOptions) (time.Time, ExprKind, bool, error) {
+	t, ok := parseOrderedDate(v, opts.DateOrder)
+	return t, ExprDate, ok, nil
+}
+
+func matchZonedDate(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	d, loc, ok := splitZone(v)
+	if !ok {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	t, k, err := parseDate(d, ref, loc)
+	return t, k, true, err
+}
+
+func matchDate(v string, ref time.Time, _ ExprOptions) (time.Time, ExprKind, bool, error) {
+	if len(v) != len(formatShortDate) && len(v) != len(formatDate) {
+		return time.Time{}, ExprInvalid, false, nil
+	}
+	t, k, err := parseDate(v, ref, time.UTC)
+	return t, k, true, err
+}
+
+// matchTimestamp parses an expression which does not match any other form as
+// an RFC 3339 timestamp or, if not strict, a compact timestamp.
+func matchTimestamp(v string, opts ExprOptions) (time.Time, ExprKind, error) {
+	if opts.Strict && !isRFC3339(v) {
+		return time.Time{}, ExprInvalid, fmt.Errorf("%w: %q", errUnrecognizedExpr, v)
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err == nil {
+		return t, ExprRFC3339, nil
+	}
+	if !opts.Strict {
+		if t, k, ok := parseCompact(v); ok {
+			return t, k, nil
+		}
+	}
+	return time.Time{}, ExprInvalid, err
+}
+
+// compactLayouts are the compact timestamp layouts accepted when an
+// expression is not an RFC 3339 timestamp, which are interpreted in UTC.
+var compactLayouts = []string{
+	"2006-01-02T15:04",
+	"2006-01-02T1504",
+	"20060102T150405",
+	"20060102T1504",
+}
+
+// parseCompact parses a compact timestamp in one of the compact layouts, which
+// has no zone and so is not an RFC 3339 timestamp, or a compact date like
+// "20060102".
+func parseCompact(s string) (time.Time, ExprKind, bool) {
+	for _, l := range compactLayouts {
+		if len(s) == len(l) {
+			if t, err := time.Parse(l, s); err == nil {
+				return t, ExprCompact, true
+			}
+		}
+	}
+	if len(s) != len("20060102") {
+		return time.Time{}, ExprInvalid, false
+	}
+	if t, err := time.Parse("20060102", s); err == nil {
+		return t, ExprDate, true
+	}
+	return time.Time{}, ExprInvalid, false
+}
+
+// isRFC3339 reports whether s has exactly the structure of an RFC 3339
+// timestamp as formatted by time.RFC3339 or time.RFC3339Nano. It does not
+// validate the values of its components.
+func isRFC3339(s string) bool {
+	const layout = "dddd-dd-ddTdd:dd:dd"
+	if len(s) < len(layout)+1 {
+		return false
+	}
+	for i := 0; i < len(layout); i++ {
+		if c := s[i]; layout[i] == 'd' && (c < '0' || c > '9') || layout[i] != 'd' && c != layout[i] {
+			return false
+		}
+	}
+	s = s[len(layout):]
+	if s[0] == '.' {
+		i := 1
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+		if i == 1 {
+			return false
+		}
+		s = s[i:]
+	}
+	if s == "Z" {
+		return true
+	}
+	return len(s) == 6 && (s[0] == '+' || s[0] == '-') && s[3] == ':' &&
+		strings.Trim(s[1:3]+s[4:], "0123456789") == ""
+}
+
+// nextWeekday returns midnight on the next occurrence of the provided weekday
+// strictly after the day of t, in t's location.
+func nextWeekday(t time.Time, d time.Weekday) time.Time {
+	n := mod(int(d-t.Weekday()), 7)
+	if n == 0 {
+		n = 7
+	}
+	return StartOfDay(t).AddDate(0, 0, n)
+}
+
+// parseEpoch parses a number of seconds since the Unix epoch, with an optional
+// sign and fraction, and returns the corresponding time in UTC.
+func parseEpoch(s string) (time.Time, error) {
+	orig := s
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	w, f, _ := strings.Cut(s, ".")
+	if w == "" || len(f) > 9 || strings.IndexFunc(w+f, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return time.Time{}, fmt.Errorf("Invalid epoch timestamp: %q", orig)
+	}
+	sec, err := strconv.ParseInt(w, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid epoch timestamp: %q", orig)
+	}
+	var nsec int64
+	if f != "" {
+		nsec, _ = strconv.ParseInt(f+strings.Repeat("0", 9-len(f)), 10, 64)
+	}
+	if neg {
+		sec, nsec = -sec, -nsec
+	}
+	return time.Unix(sec, nsec).UTC(), nil
+}
+
+// parseEndOf parses an end-of-period expression, like "end of month" or its
+// abbreviation "eom".
+func parseEndOf(s string, ref time.Time, opts ExprOptions) (time.Time, bool) {
+	switch strings.Join(strings.Fields(strings.ToLower(s)), " ") {
+	case "eod", "end of day":
+		return EndOfDay(ref), true
+	case "eow", "end of week":
+		return EndOfWeek(ref, opts.weekStart()), true
+	case "eom", "end of month":
+		return EndOfMonth(ref), true
+	case "eoy", "end of year":
+		return EndOfYear(ref), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// parseMid parses the markers "mid-month" and "mid-week", which refer to
+// midnight on the 15th of ref's month and on the configured mid-week day of
+// ref's week, respectively. The week is determined by the configured week
+// start, so when weeks begin on Thursday, for example, mid-week follows ref's
+// week start rather than preceding it.
+func parseMid(s string, ref time.Time, opts ExprOptions) (time.Time, bool) {
+	switch strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == '-' || unicode.IsSpace(r) }), " ") {
+	case "mid month", "midmonth":
+		y, m, _ := ref.Date()
+		return time.Date(y, m, 15, 0, 0, 0, 0, ref.Location()), true
+	case "mid week", "midweek":
+		ws := opts.weekStart()
+		return StartOfWeek(ref, ws).AddDate(0, 0, mod(int(opts.midWeek()-ws), 7)), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// parseOrdinal parses an ordinal day of ref's month, like "15th" or "15th of
+// the month", or one of the phrases "first of (the) month" and "last day of
+// (the) month", and returns midnight on that day. Ordinals beyond the end of
+// the month, like "31st" in November, are clamped to the last day of the
+// month.
+func parseOrdinal(s string, ref time.Time) (time.Time, bool) {
+	v := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	r, ok := strings.CutSuffix(v, " of the month")
+	if !ok {
+		r, ok = strings.CutSuffix(v, " of month")
+	}
+	if ok {
+		v = r
+	}
+	y, m, _ := ref.Date()
+	var n int
+	switch {
+	case ok && (v == "first" || v == "first day"):
+		n = 1
+	case ok && (v == "last" || v == "last day"):
+		n = daysIn(y, m)
+	default:
+		if len(v) < 3 {
+			return time.Time{}, false
+		}
+		x, err := strconv.Atoi(v[:len(v)-2])
+		if err != nil || x < 1 || x > 31 || v[:len(v)-2] != strconv.Itoa(x) || v[len(v)-2:] != ordinalSuffix(x) {
+			return time.Time{}, false
+		}
+		n = min(x, daysIn(y, m))
+	}
+	return time.Date(y, m, n, 0, 0, 0, 0, ref.Location()), true
+}
+
+// ordinalSuffix returns the English ordinal suffix for n, like "st" for 1
+// and "th" for 11.
+func ordinalSuffix(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	default:
+		return "th"
+	}
+}
+
+// parseDayPart parses a day followed by a named part of the day, like
+// "tomorrow morning" or "friday evening", where the day is one of "this",
+// "today", "tomorrow", "yesterday", or a weekday, and "tonight", which is
+// the night of ref's day. It returns the part's time of day on that day.
+func parseDayPart(s string, ref time.Time, opts ExprOptions) (time.Time, ExprKind, bool) {
+	f := strings.Fields(strings.ToLower(s))
+	if len(f) == 1 && f[0] == "tonight" {
+		f = []string{"this", "night"}
+	}
+	if len(f) != 2 {
+		return time.Time{}, ExprInvalid, false
+	}
+	c, ok := opts.dayParts()[f[1]]
+	if !ok {
+		return time.Time{}, ExprInvalid, false
+	}
+	switch f[0] {
+	case "this", "today":
+		return c.On(ref), ExprTimeOfDay, true
+	case "tomorrow":
+		return c.On(StartOfDay(ref).AddDate(0, 0, 1)), ExprTimeOfDay, true
+	case "yesterday":
+		return c.On(StartOfDay(ref).AddDate(0, 0, -1)), ExprTimeOfDay, true
+	}
+	if d, ok := parseWeekday(f[0]); ok {
+		return c.On(nextWeekday(ref, d)), ExprTimeOfDay, true
+	}
+	return time.Time{}, ExprInvalid, false
+}
+
+// parseOffset parses a signed relative offset, which is made up of one or more
+// signed durations, such as "+1d-2h". The leading sign is required, and
+// each subsequent sign begins a new term which is parsed by [ParseDuration]
+// and added to the total.
+func parseOffset(s string) (time.Duration, error) {
+	var d time.Duration
+	for s != "" {
+		n := strings.IndexAny(s[1:], "+-") + 1
+		if n == 0 {
+			n = len(s)
+		}
+		v, err := ParseDuration(s[:n])
 		if err != nil {
-			return time.Time{}, err
+			return 0, err
 		}
-		return t, nil
+		d += v
+		s = s[n:]
+	}
+	return d, nil
+}
+
+// parseBusinessDays parses a business day offset expression, either "in N
+// business days" or "N business days ago", and returns the signed number of
+// business days it refers to.
+func parseBusinessDays(s string) (int, bool) {
+	f := strings.Fields(strings.ToLower(s))
+	if len(f) != 4 {
+		return 0, false
+	}
+	var n, sign string
+	if f[0] == "in" && f[2] == "business" {
+		n, sign = f[1], "+"
+		f = f[3:]
+	} else if f[1] == "business" && f[3] == "ago" {
+		n, sign = f[0], "-"
+		f = f[2:3]
 	} else {
-		t, err := time.Parse(time.RFC3339, v)
+		return 0, false
+	}
+	if f[0] != "days" && f[0] != "day" {
+		return 0, false
+	}
+	if n == "" || n[0] == '+' || n[0] == '-' {
+		return 0, false
+	}
+	v, err := strconv.Atoi(sign + n)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// parseDate parses a date with a year, or a short date without one, in the
+// provided location. Short dates are assumed to be in the reference year.
+func parseDate(s string, ref time.Time, loc *time.Location) (time.Time, ExprKind, error) {
+	if !matchesLayout(s, formatDate) && !matchesLayout(s, formatShortDate) {
+		return time.Time{}, ExprInvalid, fmt.Errorf("%w: %q", errUnrecognizedExpr, s)
+	}
+	k := ExprDate
+	if len(s) == len(formatShortDate) {
+		s = ref.Format("2006") + "-" + s // assume current year
+		k = ExprShortDate
+	}
+	t, err := time.ParseInLocation(formatDate, s, loc)
+	if err != nil {
+		return time.Time{}, ExprInvalid, err
+	}
+	return t, k, nil
+}
+
+// matchesLayout reports whether s has the shape of a numeric layout, with a
+// digit wherever the layout has one and the same character everywhere else,
+// so that input which is merely the right length is not parsed as a date.
+func matchesLayout(s, layout string) bool {
+	if len(s) != len(layout) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if '0' <= layout[i] && layout[i] <= '9' {
+			if s[i] < '0' || s[i] > '9' {
+				return false
+			}
+		} else if s[i] != layout[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseOrderedDate parses a numeric date with the year last, like "05/01/21",
+// in the provided order. If the order is [DateOrderNone] or the input is not
+// a valid date in that form, ok is false.
+func parseOrderedDate(s string, order DateOrder) (time.Time, bool) {
+	if order == DateOrderNone {
+		return time.Time{}, false
+	}
+	sep := "/"
+	if !strings.Contains(s, sep) {
+		sep = "-"
+	}
+	f := strings.Split(s, sep)
+	if len(f) != 3 || len(f[0]) > 2 || len(f[1]) > 2 || (len(f[2]) != 2 && len(f[2]) != 4) {
+		return time.Time{}, false
+	}
+	var n [3]int
+	for i, e := range f {
+		v, err := strconv.ParseUint(e, 10, 16)
 		if err != nil {
-			return time.Time{}, err
+			return time.Time{}, false
 		}
-		return t, nil
+		n[i] = int(v)
+	}
+	m, d, y := n[0], n[1], n[2]
+	if order == DateOrderDMY {
+		m, d = d, m
+	}
+	if len(f[2]) == 2 {
+		if y < 69 {
+			y += 2000
+		} else {
+			y += 1900
+		}
+	}
+	if m < 1 || m > 12 || d < 1 || d > daysIn(y, time.Month(m)) {
+		return time.Time{}, false
+	}
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC), true
+}
+
+// splitClock splits a trailing time of day, optionally preceded by "at", from
+// an expression like "monday 9am" or "tomorrow at 5pm". If the input does not
+// end with a valid time of day preceded by something else, ok is false.
+func splitClock(s string) (string, Clock, bool) {
+	i := strings.LastIndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return "", Clock{}, false
+	}
+	a := strings.TrimSpace(s[:i])
+	if j := strings.LastIndexFunc(a, unicode.IsSpace); j >= 0 && strings.EqualFold(a[j+1:], "at") {
+		a = strings.TrimSpace(a[:j])
+	}
+	if a == "" {
+		return "", Clock{}, false
+	}
+	c, ok := parseClock(s[i+1:])
+	if !ok {
+		return "", Clock{}, false
+	}
+	return a, c, true
+}
+
+// splitOffset splits a trailing relative offset from an expression like
+// "2021-05-01 +3d". If the input does not end with a valid offset preceded by
+// something else, ok is false.
+func splitOffset(s string) (string, time.Duration, bool) {
+	i := strings.LastIndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return "", 0, false
+	}
+	a, b := strings.TrimSpace(s[:i]), s[i+1:]
+	if a == "" || b == "" || (b[0] != '+' && b[0] != '-') {
+		return "", 0, false
+	}
+	d, err := parseOffset(b)
+	if err != nil {
+		return "", 0, false
+	}
+	return a, d, true
+}
+
+// splitLocation splits a trailing location name from an expression like
+// "now in Asia/Tokyo". A single word following the last " in " is taken to be
+// a location name if it looks like one, which is to say it contains a "/" or
+// is "UTC", whether or not it is valid, so that an invalid name can be
+// reported as such. Other words, like the "5m" in "meet in 5m", are left for
+// other forms.
+func splitLocation(s string) (string, string, bool) {
+	i := lastIndexFold(s, " in ")
+	if i < 0 {
+		return "", "", false
+	}
+	a, name := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+4:])
+	if a == "" || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", false
+	}
+	if name != "UTC" && !strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return a, name, true
+}
+
+// lastIndexFold returns the index of the last instance of the ASCII substring
+// sub in s, in any case, or -1 if it is not present.
+func lastIndexFold(s, sub string) int {
+	for i := len(s) - len(sub); i >= 0; i-- {
+		if strings.EqualFold(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitZone splits a trailing timezone from an expression like
+// "2021-05-01 -0500". If the input does not end with something that looks
+// like a zone and ok is false, the input should be handled by another form.
+func splitZone(s string) (string, *time.Location, bool) {
+	i := strings.LastIndexByte(s, ' ')
+	if i < 0 {
+		return "", nil, false
+	}
+	loc, ok := parseZone(s[i+1:])
+	if !ok {
+		return "", nil, false
+	}
+	return strings.TrimSpace(s[:i]), loc, true
+}
+
+// parseZone parses a timezone, which is either a numeric offset in one of the
+// forms "-0700", "-07:00", or "-07", or one of the unambiguous names "UTC",
+// "GMT", or "Z".
+func parseZone(s string) (*time.Location, bool) {
+	switch s {
+	case "UTC", "GMT", "Z":
+		return time.UTC, true
+	}
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return nil, false
+	}
+	var hh, mm string
+	switch v := s[1:]; len(v) {
+	case 2:
+		hh = v
+	case 4:
+		hh, mm = v[:2], v[2:]
+	case 5:
+		if v[2] != ':' {
+			return nil, false
+		}
+		hh, mm = v[:2], v[3:]
+	default:
+		return nil, false
+	}
+	h, err := strconv.ParseUint(hh, 10, 8)
+	if err != nil || h > 23 {
+		return nil, false
+	}
+	var m uint64
+	if mm != "" {
+		m, err = strconv.ParseUint(mm, 10, 8)
+		if err != nil || m > 59 {
+			return nil, false
+		}
+	}
+	off := int(h)*3600 + int(m)*60
+	if s[0] == '-' {
+		off = -off
 	}
+	return time.FixedZone("", off), true
 }
@@ -2,6 +2,7 @@ package timeutil
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -46,6 +47,32 @@ func TestParseExpr(t *testing.T) {
 			Expr:   "+1d",
 			Expect: ref.Add(time.Hour * 24),
 		},
+		{
+			Ref:    ref,
+			Expr:   "+1d-2h",
+			Expect: ref.Add(time.Hour * 22),
+		},
+		{
+			Ref:    ref,
+			Expr:   "-1w+1d",
+			Expect: ref.Add(-time.Hour * 24 * 6),
+		},
+		{
+			Ref:    ref,
+			Expr:   "+1d2h",
+			Expect: ref.Add(time.Hour * 26),
+		},
+		{
+			Ref:  ref,
+			Expr: "+1d-",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
 		{
 			Ref:    ref,
 			Expr:   "05-01",
@@ -56,6 +83,62 @@ func TestParseExpr(t *testing.T) {
 			Expr:   "2021-05-01",
 			Expect: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
 		},
+		{
+			Ref:    ref,
+			Expr:   "2021-05-01 -0500",
+			Expect: time.Date(2021, 5, 1, 0, 0, 0, 0, time.FixedZone("", -5*3600)),
+		},
+		{
+			Ref:    ref,
+			Expr:   "2021-05-01 +05:30",
+			Expect: time.Date(2021, 5, 1, 0, 0, 0, 0, time.FixedZone("", 5*3600+30*60)),
+		},
+		{
+			Ref:    ref,
+			Expr:   "05-01 UTC",
+			Expect: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Ref:  ref,
+			Expr: "2021-05-01 EST",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
+		{
+			Ref:    ref,
+			Expr:   "2021-05-01 +3d",
+			Expect: time.Date(2021, 5, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Ref:    ref,
+			Expr:   "today +2h",
+			Expect: time.Date(2024, 11, 14, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			Ref:    ref,
+			Expr:   "2021-05-01 -0500 -1d+2h",
+			Expect: time.Date(2021, 4, 30, 2, 0, 0, 0, time.FixedZone("", -5*3600)),
+		},
+		{
+			Ref:    ref,
+			Expr:   "in 3 business days",
+			Expect: ref.AddDate(0, 0, 5),
+		},
+		{
+			Ref:    ref,
+			Expr:   "in 1 business day",
+			Expect: ref.AddDate(0, 0, 1),
+		},
+		{
+			Ref:    ref,
+			Expr:   "5 business days ago",
+			Expect: ref.AddDate(0, 0, -7),
+		},
 		{
 			Ref:  ref,
 			Expr: "",
@@ -88,3 +171,792 @@ func TestParseExpr(t *testing.T) {
 		}
 	}
 }
+
+func TestParseExprLocation(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ref := time.Date(2024, 11, 14, 21, 0, 0, 0, nyc) // already the next day in UTC
+	v, err := ParseExprRef("today", ref)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 11, 14, 0, 0, 0, 0, nyc), v)
+	}
+	v, err = ParseExprRef("tomorrow", time.Date(2024, 3, 9, 12, 0, 0, 0, nyc))
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, nyc), v)
+	}
+}
+
+func TestParseExprRefWith(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	opts := ExprOptions{
+		Holidays: map[Date]bool{
+			{2024, 11, 18}: true,
+		},
+	}
+	v, err := ParseExprRefWith("in 3 business days", ref, opts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, ref.AddDate(0, 0, 6), v)
+	}
+	v, err = ParseExprRefWith("2 business days ago", ref.AddDate(0, 0, 5), opts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, ref, v)
+	}
+}
+
+func TestParseExprConstants(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	launch := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	opts := ExprOptions{
+		Constants: map[string]time.Time{
+			"launch":     launch,
+			"sprint-end": time.Date(2024, 11, 22, 0, 0, 0, 0, time.UTC),
+			"now":        ref.Add(time.Hour),
+		},
+	}
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+	}{
+		{"launch", launch},
+		{" launch ", launch},
+		{"sprint-end", time.Date(2024, 11, 22, 0, 0, 0, 0, time.UTC)},
+		{"launch +2d", launch.AddDate(0, 0, 2)},
+		{"now", ref.Add(time.Hour)},
+		{"today", time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)},
+		{"-1h", ref.Add(-time.Hour)},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRefWith(test.Expr, ref, opts)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	_, err := ParseExprRefWith("Launch", ref, opts)
+	assert.Error(t, err)
+	_, err = ParseExprRef("launch", ref)
+	assert.Error(t, err)
+}
+
+func TestParseExprPastFuture(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	past := ExprOptions{MustBePast: true}
+	future := ExprOptions{MustBeFuture: true}
+	tests := []struct {
+		Expr string
+		Opts ExprOptions
+		Err  error
+	}{
+		{Expr: "1990-05-01", Opts: past},
+		{Expr: "yesterday", Opts: past},
+		{Expr: "now", Opts: past},
+		{Expr: "tomorrow", Opts: past, Err: ErrFutureNotAllowed},
+		{Expr: "+1s", Opts: past, Err: ErrFutureNotAllowed},
+		{Expr: "tomorrow", Opts: future},
+		{Expr: "yesterday +2d", Opts: future},
+		{Expr: "now", Opts: future},
+		{Expr: "1990-05-01", Opts: future, Err: ErrPastNotAllowed},
+		{Expr: "-1s", Opts: future, Err: ErrPastNotAllowed},
+	}
+	for i, test := range tests {
+		_, err := ParseExprRefWith(test.Expr, ref, test.Opts)
+		if test.Err != nil {
+			assert.ErrorIs(t, err, test.Err, "#%d", i)
+		} else {
+			assert.NoError(t, err, "#%d", i)
+		}
+	}
+}
+
+func TestParseExprAll(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	times, errs := ParseExprAll([]string{"now", "???", "2021-05-01", "", "-1h"}, ref)
+	if assert.Len(t, times, 5) && assert.Len(t, errs, 5) {
+		assert.Equal(t, []time.Time{ref, {}, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), {}, ref.Add(-time.Hour)}, times)
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1])
+		assert.NoError(t, errs[2])
+		assert.ErrorIs(t, errs[3], errNoTimeSpecified)
+		assert.NoError(t, errs[4])
+	}
+	times, errs = ParseExprAll(nil, ref)
+	assert.Len(t, times, 0)
+	assert.Len(t, errs, 0)
+}
+
+func TestParseExprRefKind(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Expect ExprKind
+	}{
+		{"now", ExprConstant},
+		{"eom", ExprConstant},
+		{"today", ExprDay},
+		{"yesterday", ExprDay},
+		{"-1h", ExprRelative},
+		{"+1d-2h", ExprRelative},
+		{"in 3 business days", ExprBusinessDays},
+		{"05-01", ExprShortDate},
+		{"05-01 UTC", ExprShortDate},
+		{"2021-05-01", ExprDate},
+		{"2021-05-01 -0500", ExprDate},
+		{"2021-05-01T10:00:00Z", ExprRFC3339},
+		{"2021-05-01T10:00", ExprCompact},
+		{"2021-05-01 +3d", ExprRelative},
+		{"fy2024", ExprFiscalYear},
+		{"@1699999999", ExprEpoch},
+		{"monday", ExprWeekday},
+		{"monday 9am", ExprTimeOfDay},
+		{"9am", ExprTimeOfDay},
+		{"mid-week", ExprDay},
+		{"mid-month", ExprDay},
+		{"tomorrow morning", ExprTimeOfDay},
+		{"friday evening", ExprTimeOfDay},
+		{"", ExprInvalid},
+		{"???", ExprInvalid},
+	}
+	for i, test := range tests {
+		_, k, _ := ParseExprRefKind(test.Expr, ref)
+		assert.Equal(t, test.Expect, k, "#%d", i)
+	}
+	assert.Equal(t, "relative", ExprRelative.String())
+	assert.Equal(t, "ExprKind(99)", ExprKind(99).String())
+}
+
+func TestParseEndOfExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	sunday := time.Sunday
+	tests := []struct {
+		Expr   string
+		Opts   ExprOptions
+		Expect time.Time
+	}{
+		{Expr: "eod", Expect: time.Date(2024, 11, 14, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "EOD", Expect: time.Date(2024, 11, 14, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "end of day", Expect: time.Date(2024, 11, 14, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "eow", Expect: time.Date(2024, 11, 17, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "Eow", Opts: ExprOptions{WeekStart: &sunday}, Expect: time.Date(2024, 11, 16, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "end of week", Expect: time.Date(2024, 11, 17, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "eom", Expect: time.Date(2024, 11, 30, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "End  of Month", Expect: time.Date(2024, 11, 30, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "eoy", Expect: time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+		{Expr: "end of year", Expect: time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRefWith(test.Expr, ref, test.Opts)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseMidExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	sunday, tuesday, thursday, saturday := time.Sunday, time.Tuesday, time.Thursday, time.Saturday
+	tests := []struct {
+		Expr   string
+		Opts   ExprOptions
+		Expect time.Time
+	}{
+		{Expr: "mid-month", Expect: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)},
+		{Expr: "Mid Month", Expect: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)},
+		{Expr: "midmonth", Expect: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)},
+		{Expr: "mid-week", Expect: time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC)},
+		{Expr: "midweek", Opts: ExprOptions{WeekStart: &sunday}, Expect: time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC)},
+		{Expr: "mid-week", Opts: ExprOptions{WeekStart: &thursday}, Expect: time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC)},
+		{Expr: "mid-week", Opts: ExprOptions{MidWeek: &tuesday}, Expect: time.Date(2024, 11, 12, 0, 0, 0, 0, time.UTC)},
+		{Expr: "mid-week", Opts: ExprOptions{WeekStart: &thursday, MidWeek: &saturday}, Expect: time.Date(2024, 11, 16, 0, 0, 0, 0, time.UTC)},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRefWith(test.Expr, ref, test.Opts)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseOrdinalExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	feb := time.Date(2023, 2, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Ref    time.Time
+		Expect time.Time
+		Err    bool
+	}{
+		{Expr: "15th", Ref: ref, Expect: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)},
+		{Expr: "1st", Ref: ref, Expect: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "22nd of the month", Ref: ref, Expect: time.Date(2024, 11, 22, 0, 0, 0, 0, time.UTC)},
+		{Expr: "3rd of month", Ref: ref, Expect: time.Date(2024, 11, 3, 0, 0, 0, 0, time.UTC)},
+		{Expr: "11th", Ref: ref, Expect: time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC)},
+		{Expr: "first of month", Ref: ref, Expect: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "First Day of the Month", Ref: ref, Expect: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "last day of month", Ref: ref, Expect: time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)},
+		{Expr: "last day of month", Ref: feb, Expect: time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{Expr: "31st", Ref: feb, Expect: time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{Expr: "29th", Ref: feb.AddDate(1, 0, 0), Expect: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{Expr: "31st", Ref: ref, Expect: time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)},
+		{Expr: "32nd", Ref: ref, Err: true},
+		{Expr: "0th", Ref: ref, Err: true},
+		{Expr: "11st", Ref: ref, Err: true},
+		{Expr: "2th", Ref: ref, Err: true},
+		{Expr: "015th", Ref: ref, Err: true},
+		{Expr: "first", Ref: ref, Err: true},
+		{Expr: "last day", Ref: ref, Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRef(test.Expr, test.Ref)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseEpochExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+		Err    bool
+	}{
+		{Expr: "@1699999999", Expect: time.Unix(1699999999, 0).UTC()},
+		{Expr: "@1699999999.500", Expect: time.Unix(1699999999, 500000000).UTC()},
+		{Expr: "@1699999999.000000001", Expect: time.Unix(1699999999, 1).UTC()},
+		{Expr: "@0", Expect: time.Unix(0, 0).UTC()},
+		{Expr: "@-1.5", Expect: time.Unix(-2, 500000000).UTC()},
+		{Expr: "@", Err: true},
+		{Expr: "@.5", Err: true},
+		{Expr: "@12x", Err: true},
+		{Expr: "@1.0000000001", Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRef(test.Expr, ref)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseOrderedDateExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	mdy := ExprOptions{DateOrder: DateOrderMDY}
+	dmy := ExprOptions{DateOrder: DateOrderDMY}
+	tests := []struct {
+		Expr   string
+		Opts   ExprOptions
+		Expect time.Time
+		Err    bool
+	}{
+		{Expr: "05/01/21", Opts: mdy, Expect: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "05/01/21", Opts: dmy, Expect: time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{Expr: "05-01-21", Opts: mdy, Expect: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "5/1/2021", Opts: mdy, Expect: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "12/31/68", Opts: mdy, Expect: time.Date(2068, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{Expr: "01/01/69", Opts: mdy, Expect: time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "31/12/99", Opts: dmy, Expect: time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{Expr: "01/01/00", Opts: dmy, Expect: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "2021-05-01", Opts: dmy, Expect: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Expr: "05/01/21", Err: true},
+		{Expr: "31/12/99", Opts: mdy, Err: true},
+		{Expr: "02/30/21", Opts: mdy, Err: true},
+		{Expr: "05/01-21", Opts: mdy, Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRefWith(test.Expr, ref, test.Opts)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseExprRefBound(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Bound  Bound
+		Expect time.Time
+	}{
+		{"2021-05-01", BoundStart, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"2021-05-01", BoundEnd, time.Date(2021, 5, 1, 23, 59, 59, 999999999, time.UTC)},
+		{"05-01", BoundEnd, time.Date(2024, 5, 1, 23, 59, 59, 999999999, time.UTC)},
+		{"2021-05-01 -0500", BoundEnd, time.Date(2021, 5, 1, 23, 59, 59, 999999999, time.FixedZone("", -5*3600))},
+		{"yesterday", BoundStart, time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", BoundEnd, time.Date(2024, 11, 13, 23, 59, 59, 999999999, time.UTC)},
+		{"now", BoundEnd, ref},
+		{"-1h", BoundEnd, ref.Add(-time.Hour)},
+		{"friday", BoundStart, time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)},
+		{"friday", BoundEnd, time.Date(2024, 11, 15, 23, 59, 59, 999999999, time.UTC)},
+		{"monday 9am", BoundEnd, time.Date(2024, 11, 18, 9, 0, 0, 0, time.UTC)},
+		{"friday evening", BoundEnd, time.Date(2024, 11, 15, 18, 0, 0, 0, time.UTC)},
+		{"fy2024", BoundStart, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"fy2024", BoundEnd, time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+		{"next fiscal year", BoundEnd, time.Date(2025, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRefBound(test.Expr, ref, test.Bound)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	_, err := ParseExprRefBound("???", ref, BoundEnd)
+	assert.Error(t, err)
+}
+
+func TestParseWeekdayExpr(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+	}{
+		{"friday", time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)},
+		{"Mon", time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC)},
+		{"WEDNESDAY", time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC)},
+		{"thursday", time.Date(2024, 11, 21, 0, 0, 0, 0, time.UTC)},
+		{"monday 9am", time.Date(2024, 11, 18, 9, 0, 0, 0, time.UTC)},
+		{"friday 17:30", time.Date(2024, 11, 15, 17, 30, 0, 0, time.UTC)},
+		{"9am", time.Date(2024, 11, 14, 9, 0, 0, 0, time.UTC)},
+		{"17:30", time.Date(2024, 11, 14, 17, 30, 0, 0, time.UTC)},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRef(test.Expr, ref)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+	v, err := ParseExprRef("sunday 9am", time.Date(2024, 3, 9, 12, 0, 0, 0, nyc)) // across spring-forward
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 3, 10, 9, 0, 0, 0, nyc), v)
+	}
+}
+
+func TestParseClockPhraseExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+	}{
+		{"half past 9", time.Date(2024, 11, 14, 9, 30, 0, 0, time.UTC)},
+		{"quarter to 5", time.Date(2024, 11, 14, 16, 45, 0, 0, time.UTC)},
+		{"3 o'clock", time.Date(2024, 11, 14, 15, 0, 0, 0, time.UTC)},
+		{"8 o'clock", time.Date(2024, 11, 14, 8, 0, 0, 0, time.UTC)},
+		{"Quarter Past 12", time.Date(2024, 11, 14, 12, 15, 0, 0, time.UTC)},
+		{"quarter past noon", time.Date(2024, 11, 14, 12, 15, 0, 0, time.UTC)},
+		{"half past midnight", time.Date(2024, 11, 14, 0, 30, 0, 0, time.UTC)},
+		{"quarter to midnight", time.Date(2024, 11, 14, 23, 45, 0, 0, time.UTC)},
+		{"quarter to 12", time.Date(2024, 11, 14, 11, 45, 0, 0, time.UTC)},
+		{"quarter to 1", time.Date(2024, 11, 14, 12, 45, 0, 0, time.UTC)},
+	}
+	for i, test := range tests {
+		v, kind, err := ParseExprRefKind(test.Expr, ref)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+			assert.Equal(t, ExprTimeOfDay, kind, "#%d", i)
+		}
+	}
+	v, err := ParseExprRef("half past 9", time.Date(2024, 11, 14, 22, 0, 0, 0, time.UTC)) // both have passed
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 11, 14, 21, 30, 0, 0, time.UTC), v)
+	}
+	v, err = ParseExprRef("quarter to 12", time.Date(2024, 11, 14, 20, 0, 0, 0, time.UTC))
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 11, 14, 23, 45, 0, 0, time.UTC), v)
+	}
+	for _, e := range []string{"half past 13", "quarter to", "half to 9", "0 o'clock"} {
+		_, err := ParseExprRef(e, ref)
+		assert.Error(t, err, e)
+	}
+}
+
+func TestParseMidnightSecondsExpr(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Ref    time.Time
+		Expect time.Time
+		Err    bool
+	}{
+		{Expr: "tod:34200", Ref: ref, Expect: time.Date(2024, 11, 14, 9, 30, 0, 0, time.UTC)},
+		{Expr: "TOD:0", Ref: ref, Expect: time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)},
+		{Expr: "tod:86399", Ref: ref, Expect: time.Date(2024, 11, 14, 23, 59, 59, 0, time.UTC)},
+		{Expr: "tod:34200", Ref: time.Date(2024, 3, 10, 12, 0, 0, 0, nyc), Expect: time.Date(2024, 3, 10, 9, 30, 0, 0, nyc)},
+		{Expr: "tod:86400", Ref: ref, Err: true},
+		{Expr: "tod:-1", Ref: ref, Err: true},
+		{Expr: "tod:", Ref: ref, Err: true},
+		{Expr: "34200", Ref: ref, Err: true},
+	}
+	for i, test := range tests {
+		v, kind, err := ParseExprRefKind(test.Expr, test.Ref)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+			assert.Equal(t, ExprTimeOfDay, kind, "#%d", i)
+		}
+	}
+}
+
+func TestParseRFC3339Expr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	ist := time.FixedZone("", 5*3600+30*60)
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+		Offset int
+	}{
+		{"2024-11-14T18:17:00Z", time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC), 0},
+		{"2024-11-14T18:17:00.5Z", time.Date(2024, 11, 14, 18, 17, 0, 500000000, time.UTC), 0},
+		{"2024-11-14T18:17:00.123456789Z", time.Date(2024, 11, 14, 18, 17, 0, 123456789, time.UTC), 0},
+		{"2024-11-14T18:17:00+05:30", time.Date(2024, 11, 14, 18, 17, 0, 0, ist), 19800},
+		{"2024-11-14T18:17:00.000000001+05:30", time.Date(2024, 11, 14, 18, 17, 0, 1, ist), 19800},
+	}
+	for i, test := range tests {
+		for _, opts := range []ExprOptions{{}, {Strict: true}} {
+			v, err := ParseExprRefWith(test.Expr, ref, opts)
+			if assert.NoError(t, err, "#%d", i) {
+				assert.True(t, test.Expect.Equal(v), "#%d", i)
+				assert.Equal(t, test.Expect.Nanosecond(), v.Nanosecond(), "#%d", i)
+				_, off := v.Zone()
+				assert.Equal(t, test.Offset, off, "#%d", i)
+				assert.Equal(t, 18, v.Hour(), "#%d", i)
+			}
+		}
+	}
+}
+
+func TestParseCompactExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+		Kind   ExprKind
+		Err    bool
+	}{
+		{Expr: "2024-11-14T18:00", Expect: time.Date(2024, 11, 14, 18, 0, 0, 0, time.UTC), Kind: ExprCompact},
+		{Expr: "2024-11-14T1800", Expect: time.Date(2024, 11, 14, 18, 0, 0, 0, time.UTC), Kind: ExprCompact},
+		{Expr: "20241114T180005", Expect: time.Date(2024, 11, 14, 18, 0, 5, 0, time.UTC), Kind: ExprCompact},
+		{Expr: "20241114T1800", Expect: time.Date(2024, 11, 14, 18, 0, 0, 0, time.UTC), Kind: ExprCompact},
+		{Expr: "20241114", Expect: time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC), Kind: ExprDate},
+		{Expr: "20241114 +1d", Expect: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC), Kind: ExprRelative},
+		{Expr: "20241314", Err: true},
+		{Expr: "2024-11-14T25:00", Err: true},
+		{Expr: "2024111", Err: true},
+		{Expr: "2024-11-14T18", Err: true},
+	}
+	for i, test := range tests {
+		v, kind, err := ParseExprRefKind(test.Expr, ref)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+			assert.Equal(t, test.Kind, kind, "#%d", i)
+		}
+	}
+	_, err := ParseExprRefWith("20241114", ref, ExprOptions{Strict: true})
+	assert.ErrorIs(t, err, errUnrecognizedExpr)
+}
+
+func TestParseLocationNameExpr(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	london := mustLoadLocation(t, "Europe/London")
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // already the next day in Tokyo
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+		Kind   ExprKind
+		Err    bool
+	}{
+		{Expr: "now in Asia/Tokyo", Expect: ref.In(tokyo), Kind: ExprConstant},
+		{Expr: "now IN Asia/Tokyo", Expect: ref.In(tokyo), Kind: ExprConstant},
+		{Expr: "today in Asia/Tokyo", Expect: time.Date(2024, 11, 15, 0, 0, 0, 0, tokyo), Kind: ExprDay},
+		{Expr: "today in Europe/London", Expect: time.Date(2024, 11, 14, 0, 0, 0, 0, london), Kind: ExprDay},
+		{Expr: "9am in Asia/Tokyo", Expect: time.Date(2024, 11, 15, 9, 0, 0, 0, tokyo), Kind: ExprTimeOfDay},
+		{Expr: "2024-11-14T18:00:00Z in Asia/Tokyo", Expect: time.Date(2024, 11, 15, 3, 0, 0, 0, tokyo), Kind: ExprRFC3339},
+		{Expr: "now in UTC", Expect: ref, Kind: ExprConstant},
+		{Expr: "now in Nowhere/Special", Err: true},
+		{Expr: "??? in Asia/Tokyo", Err: true},
+		{Expr: "in Asia/Tokyo", Err: true},
+		{Expr: "today in Local", Err: true},
+		{Expr: "today in Tokyo", Err: true},
+		{Expr: "café in Asia/Tokyo", Err: true},
+	}
+	for i, test := range tests {
+		v, kind, err := ParseExprRefKind(test.Expr, ref)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.True(t, test.Expect.Equal(v), "#%d: %v", i, v)
+			assert.Equal(t, test.Expect.Location().String(), v.Location().String(), "#%d", i)
+			assert.Equal(t, test.Kind, kind, "#%d", i)
+		}
+	}
+	_, err := ParseExprRef("now in Nowhere/Special", ref)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `Invalid location "Nowhere/Special"`)
+	}
+	_, err = ParseExprRef("now in 5m", ref)
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "Invalid location")
+		assert.Contains(t, err.Error(), `Unexpected trailing input: "in 5m" after "now"`)
+	}
+	_, err = ParseExprRef("today in Local", ref)
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "Invalid location")
+	}
+	v, err := ParseExprRef("in 3 business days", ref)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 11, 19, 18, 17, 0, 0, time.UTC), v)
+	}
+}
+
+func TestParseTrailingInputExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Expect string
+	}{
+		{"2021-05-01 extra", `Unexpected trailing input: "extra" after "2021-05-01"`},
+		{"2021-05-01 +3d extra", `Unexpected trailing input: "extra" after "2021-05-01 +3d"`},
+		{"2021-05-01 -0500 x", `Unexpected trailing input: "x" after "2021-05-01 -0500"`},
+		{"today extra", `Unexpected trailing input: "extra" after "today"`},
+		{"now and then", `Unexpected trailing input: "and then" after "now"`},
+		{"-1d extra", `Unexpected trailing input: "extra" after "-1d"`},
+		{"monday 9am sharp", `Unexpected trailing input: "sharp" after "monday 9am"`},
+		{"2024-11-14T18:00:00Z x", `Unexpected trailing input: "x" after "2024-11-14T18:00:00Z"`},
+		{"2021-05-0x", `Unrecognized expression: "2021-05-0x"`},
+		{"11-1x", `Unrecognized expression: "11-1x"`},
+		{"1699999999", `Unrecognized expression: "1699999999"`},
+	}
+	for i, test := range tests {
+		_, err := ParseExprRef(test.Expr, ref)
+		assert.EqualError(t, err, test.Expect, "#%d", i)
+	}
+
+	_, err := ParseExprRef("today extra", ref)
+	assert.ErrorIs(t, err, errTrailingInput)
+	_, err = ParseExprRefWith("today extra", ref, ExprOptions{Strict: true})
+	assert.ErrorIs(t, err, errUnrecognizedExpr)
+	_, err = ParseExprRef("now in Nowhere/Special", ref)
+	assert.ErrorIs(t, err, errInvalidLocation)
+
+	// compositions which expect trailing input are unaffected
+	for i, e := range []string{"2021-05-01 +3d", "2021-05-01 -0500", "monday 9am", "now in Asia/Tokyo"} {
+		_, err := ParseExprRef(e, ref)
+		assert.NoError(t, err, "#%d", i)
+	}
+}
+
+func TestParseDayClockExpr(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	tests := []struct {
+		Expr   string
+		Ref    time.Time
+		Expect time.Time
+		Kind   ExprKind
+		Err    bool
+	}{
+		{Expr: "yesterday at 5pm", Expect: time.Date(2024, 11, 13, 17, 0, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{Expr: "tomorrow at 09:30", Expect: time.Date(2024, 11, 15, 9, 30, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{Expr: "tomorrow 5pm", Expect: time.Date(2024, 11, 15, 17, 0, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{Expr: "today AT noon", Expect: time.Date(2024, 11, 14, 12, 0, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{Expr: "2024-12-25 at 8am", Expect: time.Date(2024, 12, 25, 8, 0, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{Expr: "12-25 8am", Expect: time.Date(2024, 12, 25, 8, 0, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{Expr: "mid-month at 9am", Expect: time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{Expr: "monday at 9am", Expect: time.Date(2024, 11, 18, 9, 0, 0, 0, time.UTC), Kind: ExprTimeOfDay},
+		{ // in the reference time's location, across the spring-forward transition
+			Expr:   "tomorrow at 5pm",
+			Ref:    time.Date(2024, 3, 9, 12, 0, 0, 0, nyc),
+			Expect: time.Date(2024, 3, 10, 17, 0, 0, 0, nyc),
+			Kind:   ExprTimeOfDay,
+		},
+		{Expr: "now at 5pm", Err: true},
+		{Expr: "at 5pm", Err: true},
+		{Expr: "tomorrow at", Err: true},
+		{Expr: "tomorrow at at 5pm", Err: true},
+	}
+	for i, test := range tests {
+		r := test.Ref
+		if r.IsZero() {
+			r = ref
+		}
+		v, kind, err := ParseExprRefKind(test.Expr, r)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+			assert.Equal(t, test.Kind, kind, "#%d", i)
+		}
+	}
+}
+
+func TestParseDayPartExpr(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	custom := ExprOptions{DayParts: map[string]Clock{"morning": {Hour: 7, Minute: 30}, "lunch": {Hour: 12}}}
+	tests := []struct {
+		Expr   string
+		Ref    time.Time
+		Opts   ExprOptions
+		Expect time.Time
+		Err    bool
+	}{
+		{Expr: "tomorrow morning", Ref: ref, Expect: time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC)},
+		{Expr: "this evening", Ref: ref, Expect: time.Date(2024, 11, 14, 18, 0, 0, 0, time.UTC)},
+		{Expr: "Today Afternoon", Ref: ref, Expect: time.Date(2024, 11, 14, 13, 0, 0, 0, time.UTC)},
+		{Expr: "yesterday night", Ref: ref, Expect: time.Date(2024, 11, 13, 21, 0, 0, 0, time.UTC)},
+		{Expr: "tonight", Ref: ref, Expect: time.Date(2024, 11, 14, 21, 0, 0, 0, time.UTC)},
+		{Expr: "friday evening", Ref: ref, Expect: time.Date(2024, 11, 15, 18, 0, 0, 0, time.UTC)},
+		{Expr: "tomorrow morning", Ref: time.Date(2024, 3, 9, 12, 0, 0, 0, nyc), Expect: time.Date(2024, 3, 10, 9, 0, 0, 0, nyc)},
+		{Expr: "tomorrow morning", Ref: ref, Opts: custom, Expect: time.Date(2024, 11, 15, 7, 30, 0, 0, time.UTC)},
+		{Expr: "this lunch", Ref: ref, Opts: custom, Expect: time.Date(2024, 11, 14, 12, 0, 0, 0, time.UTC)},
+		{Expr: "this evening", Ref: ref, Opts: custom, Err: true},
+		{Expr: "next morning", Ref: ref, Err: true},
+		{Expr: "tomorrow brunch", Ref: ref, Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRefWith(test.Expr, test.Ref, test.Opts)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestParseRelativeWordsExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+		Err    bool
+	}{
+		{Expr: "+3 days", Expect: ref.Add(day * 3)},
+		{Expr: "-2 weeks", Expect: ref.Add(-week * 2)},
+		{Expr: "- 2 weeks", Expect: ref.Add(-week * 2)},
+		{Expr: "+1 day", Expect: ref.Add(day)},
+		{Expr: "+1 hour and 30 minutes", Expect: ref.Add(time.Minute * 90)},
+		{Expr: "+1d-2h", Expect: ref.Add(time.Hour * 22)},
+		{Expr: "+3 fortnights", Err: true},
+		{Expr: "+ days", Err: true},
+		{Expr: "+1 30m", Err: true},
+		{Expr: "+1 2 hours", Err: true},
+		{Expr: "in 1 2 hours", Err: true},
+	}
+	for i, test := range tests {
+		v, kind, err := ParseExprRefKind(test.Expr, ref)
+		if test.Err {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+			assert.Equal(t, ExprRelative, kind, "#%d", i)
+		}
+	}
+}
+
+func TestParseExprStrict(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	strict := ExprOptions{Strict: true}
+	tests := []struct {
+		Expr   string
+		Expect time.Time
+		Err    bool
+	}{
+		{Expr: "2024-11-14T18:17:00Z", Expect: ref},
+		{Expr: "2024-11-14T18:17:00.5Z", Expect: ref.Add(time.Millisecond * 500)},
+		{Expr: "2024-11-14T13:17:00-05:00", Expect: time.Date(2024, 11, 14, 13, 17, 0, 0, time.FixedZone("", -5*3600))},
+		{Expr: "2024-11-14", Expect: time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)},
+		{Expr: "-1h", Expect: ref.Add(-time.Hour)},
+		{Expr: "2024-11-14T18:17:00", Err: true},
+		{Expr: "2024-11-14 18:17:00Z", Err: true},
+		{Expr: "2024-11-14T18:17Z", Err: true},
+		{Expr: "2024-11-14T18:17:00.Z", Err: true},
+		{Expr: "2024-11-14T18:17:00+0500", Err: true},
+		{Expr: "2024-13-14T18:17:00Z", Err: true},
+		{Expr: "2024-13-14", Err: true},
+		{Expr: "whenever", Err: true},
+	}
+	for i, test := range tests {
+		v, err := ParseExprRefWith(test.Expr, ref, strict)
+		if test.Err {
+			if assert.Error(t, err, "#%d", i) {
+				assert.True(t, errors.Is(err, errUnrecognizedExpr), "#%d", i)
+				assert.Equal(t, fmt.Sprintf("Unrecognized expression: %q", test.Expr), err.Error(), "#%d", i)
+			}
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.True(t, test.Expect.Equal(v), "#%d: expected %v, got %v", i, test.Expect, v)
+		}
+	}
+	_, err := ParseExprRefWith("", ref, strict)
+	assert.True(t, errors.Is(err, errNoTimeSpecified))
+	_, err = ParseExprRef("whenever", ref)
+	if assert.Error(t, err) {
+		assert.False(t, errors.Is(err, errUnrecognizedExpr))
+	}
+}
+
+func TestParseExprDelta(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	tests := []struct {
+		Expr   string
+		Expect time.Duration
+		Error  bool
+	}{
+		{"now", 0, false},
+		{"-10d", -time.Hour * 240, false},
+		{"+1h30m", time.Minute * 90, false},
+		{"tomorrow", time.Hour*5 + time.Minute*43, false},
+		{"yesterday", -(time.Hour*42 + time.Minute*17), false},
+		{"2024-11-14", -(time.Hour*18 + time.Minute*17), false},
+		{"2024-11-15T18:17:00Z", time.Hour * 24, false},
+		{"0001-01-01T00:00:00Z", minDuration, false},
+		{"whenever", 0, true},
+		{"", 0, true},
+	}
+	for i, test := range tests {
+		d, err := ParseExprDelta(test.Expr, ref)
+		if test.Error {
+			assert.Error(t, err, "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, d, "#%d", i)
+		}
+	}
+}
+
+func BenchmarkParseExpr(b *testing.B) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	exprs := []string{
+		"now",
+		"today",
+		"yesterday",
+		"-10d",
+		"+1h30m",
+		"monday",
+		"9am",
+		"eom",
+		"tomorrow morning",
+		"monday 9am",
+		"in 3 business days",
+		"2024-11-14",
+		"11-14",
+		"2021-05-01 +3d",
+		"2024-11-14T18:00:00Z",
+		"2024-11-14T18:17:00.5-05:00",
+		"@1699999999",
+		"fy2024",
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range exprs {
+			if _, err := ParseExprRef(e, ref); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package timeutil
+
+import (
+	"time"
+)
+
+// DurationParts is a duration decomposed into days, hours, minutes, seconds,
+// milliseconds, microseconds, and nanoseconds, which are the components
+// displayed by [FormatDuration]. Days are exactly 24 hours.
+type DurationParts struct {
+	Days    int
+	Hours   int
+	Minutes int
+	Seconds int
+	Millis  int
+	Micros  int
+	Nanos   int
+}
+
+// Breakdown decomposes a duration into its parts, so that each part other
+// than Days is less than the next larger unit, like 0-23 hours. The parts of
+// a negative duration are all negative or zero, and the parts of any duration,
+// including the minimum, can be recomposed exactly by [FromParts].
+func Breakdown(d time.Duration) DurationParts {
+	// the magnitude is computed as an unsigned value so that the minimum
+	// duration, which cannot be negated, is decomposed correctly
+	v := uint64(d)
+	if d < 0 {
+		v = -v
+	}
+	next := func(n uint64) int {
+		p := v % n
+		v /= n
+		return int(p)
+	}
+	p := DurationParts{
+		Nanos:   next(1000),
+		Micros:  next(1000),
+		Millis:  next(1000),
+		Seconds: next(60),
+		Minutes: next(60),
+		Hours:   next(24),
+	}
+	p.Days = int(v)
+	if d < 0 {
+		p = DurationParts{
+			Days:    -p.Days,
+			Hours:   -p.Hours,
+			Minutes: -p.Minutes,
+			Seconds: -p.Seconds,
+			Millis:  -p.Millis,
+			Micros:  -p.Micros,
+			Nanos:   -p.Nanos,
+		}
+	}
+	return p
+}
+
+// FromParts recomposes a duration from its parts, which is the sum of the
+// parts in their units. The parts need not be normalized, so 90 minutes is the
+// same as 1 hour and 30 minutes. Like arithmetic on time.Duration, the result
+// is not checked for overflow.
+func FromParts(p DurationParts) time.Duration {
+	return time.Duration(p.Days)*day +
+		time.Duration(p.Hours)*time.Hour +
+		time.Duration(p.Minutes)*time.Minute +
+		time.Duration(p.Seconds)*time.Second +
+		time.Duration(p.Millis)*time.Millisecond +
+		time.Duration(p.Micros)*time.Microsecond +
+		time.Duration(p.Nanos)*time.Nanosecond
+}
+
+// WholeDays returns the whole number of days in the duration, which is the
+// days component displayed by [FormatDuration].
+func (d Duration) WholeDays() int {
+	return Breakdown(time.Duration(d)).Days
+}
+
+// WholeHours returns the hours component of the duration after whole days are
+// removed, so 25 hours is one day and one hour.
+func (d Duration) WholeHours() int {
+	return Breakdown(time.Duration(d)).Hours
+}
+
+// WholeMinutes returns the minutes component of the duration after whole hours
+// are removed.
+func (d Duration) WholeMinutes() int {
+	return Breakdown(time.Duration(d)).Minutes
+}
+
+// WholeSeconds returns the seconds component of the duration after whole
+// minutes are removed.
+func (d Duration) WholeSeconds() int {
+	return Breakdown(time.Duration(d)).Seconds
+}
+
+// WholeMillis returns the milliseconds component of the duration after whole
+// seconds are removed.
+func (d Duration) WholeMillis() int {
+	return Breakdown(time.Duration(d)).Millis
+}
+
+// WholeMicros returns the microseconds component of the duration after whole
+// milliseconds are removed.
+func (d Duration) WholeMicros() int {
+	return Breakdown(time.Duration(d)).Micros
+}
+
+// WholeNanos returns the nanoseconds component of the duration after whole
+// microseconds are removed.
+func (d Duration) WholeNanos() int {
+	return Breakdown(time.Duration(d)).Nanos
+}
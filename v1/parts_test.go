@@ -0,0 +1,62 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakdown(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Expect   DurationParts
+	}{
+		{0, DurationParts{}},
+		{time.Nanosecond, DurationParts{Nanos: 1}},
+		{time.Minute * 90, DurationParts{Hours: 1, Minutes: 30}},
+		{
+			day*3 + time.Hour*4 + time.Minute*5 + time.Second*6 + time.Millisecond*7 + time.Microsecond*8 + time.Nanosecond*9,
+			DurationParts{Days: 3, Hours: 4, Minutes: 5, Seconds: 6, Millis: 7, Micros: 8, Nanos: 9},
+		},
+		{week*2 + time.Second, DurationParts{Days: 14, Seconds: 1}},
+		{-(day + time.Millisecond*500), DurationParts{Days: -1, Millis: -500}},
+		{maxDuration, DurationParts{Days: 106751, Hours: 23, Minutes: 47, Seconds: 16, Millis: 854, Micros: 775, Nanos: 807}},
+		{minDuration, DurationParts{Days: -106751, Hours: -23, Minutes: -47, Seconds: -16, Millis: -854, Micros: -775, Nanos: -808}},
+	}
+	for i, test := range tests {
+		p := Breakdown(test.Duration)
+		assert.Equal(t, test.Expect, p, "#%d", i)
+		assert.Equal(t, test.Duration, FromParts(p), "#%d", i)
+	}
+	assert.Equal(t, time.Minute*90, FromParts(DurationParts{Minutes: 90}))
+	assert.Equal(t, time.Minute*30, FromParts(DurationParts{Hours: 1, Minutes: -30}))
+}
+
+func TestDurationWholeUnits(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Format   string
+		Expect   [7]int
+	}{
+		{0, "0s", [7]int{}},
+		{time.Hour * 25, "1d1h", [7]int{1, 1, 0, 0, 0, 0, 0}},
+		{time.Minute * 90, "1h30m", [7]int{0, 1, 30, 0, 0, 0, 0}},
+		{day*2 + time.Second*61 + time.Millisecond*5, "2d1m1s5ms", [7]int{2, 0, 1, 1, 5, 0, 0}},
+		{time.Microsecond*1001 + time.Nanosecond*3, "1ms1µs3ns", [7]int{0, 0, 0, 0, 1, 1, 3}},
+		{-(time.Hour*25 + time.Minute), "-1d1h1m", [7]int{-1, -1, -1, 0, 0, 0, 0}},
+	}
+	for i, test := range tests {
+		d := Duration(test.Duration)
+		assert.Equal(t, test.Format, FormatDuration(test.Duration), "#%d", i)
+		assert.Equal(t, test.Expect, [7]int{
+			d.WholeDays(),
+			d.WholeHours(),
+			d.WholeMinutes(),
+			d.WholeSeconds(),
+			d.WholeMillis(),
+			d.WholeMicros(),
+			d.WholeNanos(),
+		}, "#%d", i)
+	}
+}
@@ -0,0 +1,36 @@
+package timeutil
+
+import (
+	"net/url"
+	"time"
+)
+
+// DurationToQuery formats a duration for use as a query parameter value,
+// like "1h30m". It is formatted like [FormatDuration], but with the ASCII
+// "us" suffix for microseconds so that it does not need to be escaped.
+func DurationToQuery(d time.Duration) string {
+	return FormatDurationWith(d, FormatOptions{ASCII: true})
+}
+
+// DurationFromQuery parses a query parameter value as a duration, as
+// accepted by [ParseDuration].
+func DurationFromQuery(v string) (time.Duration, error) {
+	return ParseDuration(v)
+}
+
+// SetDuration sets the value of key in the query parameters to a duration,
+// as formatted by [DurationToQuery], replacing any existing values.
+func SetDuration(values url.Values, key string, d time.Duration) {
+	values.Set(key, DurationToQuery(d))
+}
+
+// GetDuration parses the first value of key in the query parameters as a
+// duration, as accepted by [DurationFromQuery]. Like url.Values.Get, if there
+// is no value for the key, it returns zero and no error.
+func GetDuration(values url.Values, key string) (time.Duration, error) {
+	v, ok := values[key]
+	if !ok || len(v) == 0 {
+		return 0, nil
+	}
+	return DurationFromQuery(v[0])
+}
@@ -0,0 +1,54 @@
+package timeutil
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationQuery(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Expect   string
+	}{
+		{time.Minute * 90, "1h30m"},
+		{day + time.Microsecond*5, "1d5us"},
+		{-time.Second, "-1s"},
+		{0, "0s"},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, DurationToQuery(test.Duration), "#%d", i)
+		v, err := DurationFromQuery(test.Expect)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Duration, v, "#%d", i)
+		}
+
+		values := url.Values{}
+		SetDuration(values, "ttl", test.Duration)
+		q, err := url.ParseQuery(values.Encode())
+		if assert.NoError(t, err, "#%d", i) {
+			v, err := GetDuration(q, "ttl")
+			if assert.NoError(t, err, "#%d", i) {
+				assert.Equal(t, test.Duration, v, "#%d", i)
+			}
+		}
+	}
+
+	q, err := url.ParseQuery("ttl=1h30m&ttl=5m&bad=1x")
+	if assert.NoError(t, err) {
+		v, err := GetDuration(q, "ttl")
+		if assert.NoError(t, err) {
+			assert.Equal(t, time.Minute*90, v)
+		}
+		SetDuration(q, "ttl", time.Second)
+		assert.Equal(t, []string{"1s"}, q["ttl"])
+		_, err = GetDuration(q, "bad")
+		assert.Error(t, err)
+		v, err = GetDuration(q, "missing")
+		if assert.NoError(t, err) {
+			assert.Equal(t, time.Duration(0), v)
+		}
+	}
+}
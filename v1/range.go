@@ -0,0 +1,409 @@
+package timeutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TimeRange is a half-open range of time which includes Start and excludes
+// End. A range constructed as a struct literal is used as-is, so if Start is
+// after End, the range is empty and its Duration is negative; use
+// [NewTimeRange] or [TimeRange.Normalized] to order its bounds.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewTimeRange returns the range between a and b, in whichever order they are
+// provided, so that Start is never after End.
+func NewTimeRange(a, b time.Time) TimeRange {
+	return TimeRange{Start: a, End: b}.Normalized()
+}
+
+// Normalized returns the range with its bounds swapped if Start is after End.
+func (r TimeRange) Normalized() TimeRange {
+	if r.Start.After(r.End) {
+		return TimeRange{Start: r.End, End: r.Start}
+	}
+	return r
+}
+
+// Duration returns the length of the range.
+func (r TimeRange) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Contains reports whether t falls within the range.
+func (r TimeRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// Progress returns the fraction of the range which has elapsed as of the
+// provided time. It is 0 before Start, 1 at or after End, and increases
+// linearly in between. A zero-length range is considered to be complete
+// at its Start, so Progress returns 1 there.
+func (r TimeRange) Progress(at time.Time) float64 {
+	switch {
+	case !at.Before(r.End):
+		return 1
+	case !at.After(r.Start):
+		return 0
+	default:
+		return float64(at.Sub(r.Start)) / float64(r.Duration())
+	}
+}
+
+// Split divides the range into consecutive sub-ranges of width by, the last
+// of which may be shorter. When by is a whole number of days, sub-ranges are
+// stepped on the calendar in Start's location so that they stay aligned to
+// the same wall-clock time across daylight saving transitions. If by is not
+// positive or the range is empty, Split returns nil.
+func (r TimeRange) Split(by time.Duration) []TimeRange {
+	if by <= 0 || !r.Start.Before(r.End) {
+		return nil
+	}
+	step := func(t time.Time) time.Time { return t.Add(by) }
+	if by%day == 0 {
+		n := int(by / day)
+		step = func(t time.Time) time.Time { return t.AddDate(0, 0, n) }
+	}
+	var s []TimeRange
+	for t := r.Start; t.Before(r.End); {
+		e := step(t)
+		if e.After(r.End) {
+			e = r.End
+		}
+		s = append(s, TimeRange{Start: t, End: e})
+		t = e
+	}
+	return s
+}
+
+// Subtract returns the parts of the range which are not covered by other,
+// in order. The result is empty if other covers the whole range, holds two
+// ranges if other falls strictly within it, and otherwise holds one. If the
+// ranges do not overlap, the result is the range unchanged. If the range is
+// empty, Subtract returns nil.
+func (r TimeRange) Subtract(other TimeRange) []TimeRange {
+	if !r.Start.Before(r.End) {
+		return nil
+	}
+	if !other.Start.Before(other.End) || !other.Start.Before(r.End) || !other.End.After(r.Start) {
+		return []TimeRange{r}
+	}
+	var s []TimeRange
+	if r.Start.Before(other.Start) {
+		s = append(s, TimeRange{Start: r.Start, End: other.Start})
+	}
+	if other.End.Before(r.End) {
+		s = append(s, TimeRange{Start: other.End, End: r.End})
+	}
+	return s
+}
+
+// EachDay returns midnight at the start of every day which overlaps the range,
+// in the provided location, or Start's location if it is nil. The first day
+// is the one containing Start, so it may begin before the range does. Days
+// are stepped on the calendar, so each is returned exactly once even when a
+// daylight saving transition makes it longer or shorter than 24 hours. If the
+// range is empty, EachDay returns nil.
+func EachDay(r TimeRange, loc *time.Location) []time.Time {
+	return collectEach(func(fn func(time.Time) bool) { RangeEachDay(r, loc, fn) })
+}
+
+// EachWeek returns midnight at the start of every week which overlaps the
+// range, where weeks begin on the provided weekday, like [EachDay].
+func EachWeek(r TimeRange, loc *time.Location, weekStart time.Weekday) []time.Time {
+	return collectEach(func(fn func(time.Time) bool) { RangeEachWeek(r, loc, weekStart, fn) })
+}
+
+// EachMonth returns midnight at the start of every month which overlaps the
+// range, like [EachDay].
+func EachMonth(r TimeRange, loc *time.Location) []time.Time {
+	return collectEach(func(fn func(time.Time) bool) { RangeEachMonth(r, loc, fn) })
+}
+
+// RangeEachDay calls fn with the start of every day which overlaps the range,
+// as described by [EachDay], stopping early if fn returns false.
+func RangeEachDay(r TimeRange, loc *time.Location, fn func(day time.Time) bool) {
+	rangeEach(r, loc, StartOfDay, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }, fn)
+}
+
+// RangeEachWeek calls fn with the start of every week which overlaps the
+// range, as described by [EachWeek], stopping early if fn returns false.
+func RangeEachWeek(r TimeRange, loc *time.Location, weekStart time.Weekday, fn func(week time.Time) bool) {
+	start := func(t time.Time) time.Time { return StartOfWeek(t, weekStart) }
+	rangeEach(r, loc, start, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }, fn)
+}
+
+// RangeEachMonth calls fn with the start of every month which overlaps the
+// range, as described by [EachMonth], stopping early if fn returns false.
+func RangeEachMonth(r TimeRange, loc *time.Location, fn func(month time.Time) bool) {
+	rangeEach(r, loc, StartOfMonth, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }, fn)
+}
+
+// rangeEach calls fn with the start of every period which overlaps the range,
+// where start finds the beginning of the period containing a time and next
+// steps from the beginning of one period to the beginning of the next.
+func rangeEach(r TimeRange, loc *time.Location, start, next func(time.Time) time.Time, fn func(time.Time) bool) {
+	if !r.Start.Before(r.End) {
+		return
+	}
+	if loc == nil {
+		loc = r.Start.Location()
+	}
+	for t := start(r.Start.In(loc)); t.Before(r.End); t = next(t) {
+		if !fn(t) {
+			return
+		}
+	}
+}
+
+func collectEach(each func(func(time.Time) bool)) []time.Time {
+	var s []time.Time
+	each(func(t time.Time) bool {
+		s = append(s, t)
+		return true
+	})
+	return s
+}
+
+func (r TimeRange) String() string {
+	return r.Start.Format(time.RFC3339Nano) + ".." + r.End.Format(time.RFC3339Nano)
+}
+
+type timeRangeJSON struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (r TimeRange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(timeRangeJSON{Start: r.Start, End: r.End})
+}
+
+func (r *TimeRange) UnmarshalJSON(data []byte) error {
+	var v timeRangeJSON
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*r = TimeRange{Start: v.Start, End: v.End}
+	return nil
+}
+
+// MarshalText formats the range as two RFC 3339 timestamps separated by
+// "..", which is a range expression that [ParseRangeExprRef] accepts.
+func (r TimeRange) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText parses a range expression with [ParseRangeExpr], so in
+// addition to the form produced by MarshalText, relative expressions like
+// "yesterday..now" are accepted and evaluated relative to the current time.
+func (r *TimeRange) UnmarshalText(text []byte) error {
+	v, err := ParseRangeExpr(string(text))
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// ParseRangeExpr is a convenience interface to [ParseRangeExprRef] which
+// provides [Now] as the reference time.
+func ParseRangeExpr(s string) (TimeRange, error) {
+	return ParseRangeExprRef(s, Now())
+}
+
+// ParseRangeExprRef parses a range expression and returns the range of time
+// that it represents, relative to the provided reference time.
+//
+// This function supports the following inputs:
+//
+//   - A calendar period relative to the reference time, in the form
+//     "(this|last|next) (day|week|month|year)", which refers to the entire
+//     period in the reference time's location. For example, "this week" refers
+//     to the range from midnight at the start of the current week to midnight
+//     at the start of the next week. Weeks begin on [DefaultWeekStart] unless
+//     configured otherwise via [ParseRangeExprRefWith];
+//
+//   - A rolling period relative to the reference time, in the form
+//     "(in the) (last|next) N (days|weeks|months|years)". The far end of the
+//     range is extended to a whole day, while the near end is the reference
+//     time itself, so "last 7 days" refers to the range from midnight at the
+//     start of the day 7 days before the reference time up to the reference
+//     time, and "next 30 days" refers to the range from the reference time up
+//     to midnight at the end of the day 30 days after it;
+//
+//   - A fiscal year, in the form "fyYYYY" or "(this|last|next) fiscal year",
+//     which refers to the entire fiscal year. See [ParseExprRefWith] for how
+//     fiscal years are configured and labeled;
+//
+//   - A time expression, as supported by [ParseExprRef], preceded by "since"
+//     or "until", for example "since -7d", which refers to the range from that
+//     time up to the reference time, or from the reference time up to that
+//     time, respectively. The time must not fall on the wrong side of the
+//     reference time, so "since tomorrow" is an error wrapping
+//     [ErrFutureNotAllowed] and "until yesterday" is an error wrapping
+//     [ErrPastNotAllowed];
+//
+//   - Two time expressions, as supported by [ParseExprRef], separated by "..",
+//     for example "yesterday..now", which refers to the range between them.
+//
+// Any other input, including an empty string is an error.
+func ParseRangeExprRef(s string, ref time.Time) (TimeRange, error) {
+	return ParseRangeExprRefWith(s, ref, ExprOptions{})
+}
+
+// ParseRangeExprRefWith parses a range expression like [ParseRangeExprRef],
+// using the provided options.
+func ParseRangeExprRefWith(s string, ref time.Time, opts ExprOptions) (TimeRange, error) {
+	v := strings.TrimSpace(s)
+	if v == "" {
+		return TimeRange{}, errNoTimeSpecified
+	}
+	if r, ok := parsePeriod(v, ref, opts); ok {
+		return r, nil
+	}
+	if r, ok := parseRollingPeriod(v, ref); ok {
+		return r, nil
+	}
+	if r, ok := parseFiscalYear(v, ref, opts); ok {
+		return r, nil
+	}
+	if e, ok := cutKeyword(v, "since"); ok {
+		o := opts
+		o.MustBePast = true
+		start, err := ParseExprRefWith(e, ref, o)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		return TimeRange{Start: start, End: ref}, nil
+	}
+	if e, ok := cutKeyword(v, "until"); ok {
+		o := opts
+		o.MustBeFuture = true
+		end, err := ParseExprRefWith(e, ref, o)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		return TimeRange{Start: ref, End: end}, nil
+	}
+	if a, b, ok := strings.Cut(v, ".."); ok {
+		start, err := ParseExprRefWith(a, ref, opts)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		end, err := ParseExprRefWith(b, ref, opts)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		return TimeRange{Start: start, End: end}, nil
+	}
+	return TimeRange{}, fmt.Errorf("Unrecognized range expression: %q", v)
+}
+
+// cutKeyword returns s without its leading keyword and the whitespace which
+// follows it, if s begins with the keyword in any case.
+func cutKeyword(s, keyword string) (string, bool) {
+	n := len(keyword)
+	if len(s) > n && strings.EqualFold(s[:n], keyword) && unicode.IsSpace(rune(s[n])) {
+		return s[n+1:], true
+	}
+	return "", false
+}
+
+// parsePeriod parses a calendar period relative to the reference time, like
+// "this week" or "last month".
+func parsePeriod(s string, ref time.Time, opts ExprOptions) (TimeRange, bool) {
+	f := strings.Fields(strings.ToLower(s))
+	if len(f) != 2 {
+		return TimeRange{}, false
+	}
+	var n int
+	switch f[0] {
+	case "this":
+		n = 0
+	case "last":
+		n = -1
+	case "next":
+		n = 1
+	default:
+		return TimeRange{}, false
+	}
+	var start time.Time
+	var step func(time.Time, int) time.Time
+	switch f[1] {
+	case "day":
+		start, step = StartOfDay(ref), addDays
+	case "week":
+		start, step = StartOfWeek(ref, opts.weekStart()), addWeeks
+	case "month":
+		start, step = StartOfMonth(ref), addMonthsTo
+	case "year":
+		start, step = StartOfYear(ref), addYears
+	default:
+		return TimeRange{}, false
+	}
+	start = step(start, n)
+	return TimeRange{Start: start, End: step(start, 1)}, true
+}
+
+// parseRollingPeriod parses a rolling period relative to the reference time,
+// like "last 7 days" or "in the next 3 months".
+func parseRollingPeriod(s string, ref time.Time) (TimeRange, bool) {
+	f := strings.Fields(strings.ToLower(s))
+	if len(f) == 5 && f[0] == "in" && f[1] == "the" {
+		f = f[2:]
+	} else if len(f) == 4 && f[0] == "the" {
+		f = f[1:]
+	}
+	if len(f) != 3 {
+		return TimeRange{}, false
+	}
+	n, err := strconv.Atoi(f[1])
+	if err != nil || n < 1 || f[1][0] == '+' {
+		return TimeRange{}, false
+	}
+	var step func(time.Time, int) time.Time
+	switch strings.TrimSuffix(f[2], "s") {
+	case "day":
+		step = addDays
+	case "week":
+		step = addWeeks
+	case "month":
+		step = addMonthsTo
+	case "year":
+		step = addYears
+	default:
+		return TimeRange{}, false
+	}
+	switch f[0] {
+	case "last":
+		return TimeRange{Start: StartOfDay(step(ref, -n)), End: ref}, true
+	case "next":
+		return TimeRange{Start: ref, End: StartOfDay(step(ref, n)).AddDate(0, 0, 1)}, true
+	default:
+		return TimeRange{}, false
+	}
+}
+
+func addDays(t time.Time, n int) time.Time {
+	return t.AddDate(0, 0, n)
+}
+
+func addWeeks(t time.Time, n int) time.Time {
+	return t.AddDate(0, 0, n*7)
+}
+
+func addMonthsTo(t time.Time, n int) time.Time {
+	return t.AddDate(0, n, 0)
+}
+
+func addYears(t time.Time, n int) time.Time {
+	return t.AddDate(n, 0, 0)
+}
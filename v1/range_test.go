@@ -0,0 +1,509 @@
+package timeutil
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRangeExpr(t *testing.T) {
+	ref := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) // a Thursday
+	sunday := time.Sunday
+	tests := []struct {
+		Expr   string
+		Opts   ExprOptions
+		Expect TimeRange
+		Err    func(error) error
+	}{
+		{
+			Expr: "this week",
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "this week",
+			Opts: ExprOptions{WeekStart: &sunday},
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 11, 17, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "next week",
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 11, 25, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "last month",
+			Expect: TimeRange{
+				Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "this year",
+			Expect: TimeRange{
+				Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "Last Day",
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "last 7 days",
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 7, 0, 0, 0, 0, time.UTC),
+				End:   ref,
+			},
+		},
+		{
+			Expr: "in the Last 1 Day",
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC),
+				End:   ref,
+			},
+		},
+		{
+			Expr: "next 30 days",
+			Expect: TimeRange{
+				Start: ref,
+				End:   time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "last 3 months",
+			Expect: TimeRange{
+				Start: time.Date(2024, 8, 14, 0, 0, 0, 0, time.UTC),
+				End:   ref,
+			},
+		},
+		{
+			Expr: "the next 2 weeks",
+			Expect: TimeRange{
+				Start: ref,
+				End:   time.Date(2024, 11, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "last 1 year",
+			Expect: TimeRange{
+				Start: time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC),
+				End:   ref,
+			},
+		},
+		{
+			Expr: "yesterday..now",
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC),
+				End:   ref,
+			},
+		},
+		{
+			Expr: "since yesterday",
+			Expect: TimeRange{
+				Start: time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC),
+				End:   ref,
+			},
+		},
+		{
+			Expr: "Since  -7d",
+			Expect: TimeRange{
+				Start: ref.AddDate(0, 0, -7),
+				End:   ref,
+			},
+		},
+		{
+			Expr: "until tomorrow",
+			Expect: TimeRange{
+				Start: ref,
+				End:   time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Expr: "since now",
+			Expect: TimeRange{
+				Start: ref,
+				End:   ref,
+			},
+		},
+		{
+			Expr: "since tomorrow",
+			Err: func(err error) error {
+				if errors.Is(err, ErrFutureNotAllowed) {
+					return nil
+				} else {
+					return errors.New("Expected a future time error")
+				}
+			},
+		},
+		{
+			Expr: "until yesterday",
+			Err: func(err error) error {
+				if errors.Is(err, ErrPastNotAllowed) {
+					return nil
+				} else {
+					return errors.New("Expected a past time error")
+				}
+			},
+		},
+		{
+			Expr: "since",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
+		{
+			Expr: "until whenever",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
+		{
+			Expr: "",
+			Err: func(err error) error {
+				if errors.Is(err, errNoTimeSpecified) {
+					return nil
+				} else {
+					return err
+				}
+			},
+		},
+		{
+			Expr: "last 0 days",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
+		{
+			Expr: "last 3 fortnights",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
+		{
+			Expr: "last fortnight",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
+		{
+			Expr: "yesterday..???",
+			Err: func(err error) error {
+				if err != nil {
+					return nil
+				} else {
+					return errors.New("Expected an error")
+				}
+			},
+		},
+	}
+	for i, test := range tests {
+		v, err := ParseRangeExprRefWith(test.Expr, ref, test.Opts)
+		if test.Err != nil {
+			assert.NoError(t, test.Err(err), "#%d", i)
+		} else if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, v, "#%d", i)
+		}
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	r := TimeRange{
+		Start: time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, day, r.Duration())
+	assert.True(t, r.Contains(r.Start))
+	assert.True(t, r.Contains(r.Start.Add(time.Hour)))
+	assert.False(t, r.Contains(r.End))
+	assert.False(t, r.Contains(r.Start.Add(-time.Nanosecond)))
+}
+
+func TestNewTimeRange(t *testing.T) {
+	a := time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC)
+	b := time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC)
+	expect := TimeRange{Start: a, End: b}
+	assert.Equal(t, expect, NewTimeRange(a, b))
+	assert.Equal(t, expect, NewTimeRange(b, a))
+	assert.Equal(t, day, NewTimeRange(b, a).Duration())
+	assert.True(t, NewTimeRange(b, a).Contains(a))
+	assert.Equal(t, TimeRange{Start: a, End: a}, NewTimeRange(a, a))
+
+	r := TimeRange{Start: b, End: a}
+	assert.Equal(t, -day, r.Duration())
+	assert.False(t, r.Contains(a))
+	assert.Equal(t, expect, r.Normalized())
+	assert.Equal(t, expect, expect.Normalized())
+}
+
+func TestTimeRangeProgress(t *testing.T) {
+	r := TimeRange{
+		Start: time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, 0.0, r.Progress(r.Start.Add(-time.Hour)))
+	assert.Equal(t, 0.0, r.Progress(r.Start))
+	assert.Equal(t, 0.25, r.Progress(r.Start.Add(time.Hour*6)))
+	assert.Equal(t, 0.5, r.Progress(r.Start.Add(time.Hour*12)))
+	assert.Equal(t, 1.0, r.Progress(r.End))
+	assert.Equal(t, 1.0, r.Progress(r.End.Add(time.Hour)))
+
+	z := TimeRange{Start: r.Start, End: r.Start}
+	assert.Equal(t, 0.0, z.Progress(z.Start.Add(-time.Hour)))
+	assert.Equal(t, 1.0, z.Progress(z.Start))
+	assert.Equal(t, 1.0, z.Progress(z.Start.Add(time.Hour)))
+}
+
+func TestTimeRangeSplit(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	utc := func(h int) time.Time {
+		return time.Date(2024, 11, 14, h, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		Range  TimeRange
+		By     time.Duration
+		Expect []TimeRange
+	}{
+		{ // even division
+			Range: TimeRange{Start: utc(0), End: utc(6)},
+			By:    time.Hour * 2,
+			Expect: []TimeRange{
+				{Start: utc(0), End: utc(2)},
+				{Start: utc(2), End: utc(4)},
+				{Start: utc(4), End: utc(6)},
+			},
+		},
+		{ // remainder
+			Range: TimeRange{Start: utc(0), End: utc(5)},
+			By:    time.Hour * 2,
+			Expect: []TimeRange{
+				{Start: utc(0), End: utc(2)},
+				{Start: utc(2), End: utc(4)},
+				{Start: utc(4), End: utc(5)},
+			},
+		},
+		{ // daily across the spring-forward transition
+			Range: TimeRange{
+				Start: time.Date(2024, 3, 9, 0, 0, 0, 0, nyc),
+				End:   time.Date(2024, 3, 12, 0, 0, 0, 0, nyc),
+			},
+			By: day,
+			Expect: []TimeRange{
+				{Start: time.Date(2024, 3, 9, 0, 0, 0, 0, nyc), End: time.Date(2024, 3, 10, 0, 0, 0, 0, nyc)},
+				{Start: time.Date(2024, 3, 10, 0, 0, 0, 0, nyc), End: time.Date(2024, 3, 11, 0, 0, 0, 0, nyc)},
+				{Start: time.Date(2024, 3, 11, 0, 0, 0, 0, nyc), End: time.Date(2024, 3, 12, 0, 0, 0, 0, nyc)},
+			},
+		},
+		{
+			Range: TimeRange{Start: utc(0), End: utc(5)},
+			By:    0,
+		},
+		{
+			Range: TimeRange{Start: utc(0), End: utc(5)},
+			By:    -time.Hour,
+		},
+		{
+			Range: TimeRange{Start: utc(5), End: utc(5)},
+			By:    time.Hour,
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, test.Range.Split(test.By), "#%d", i)
+	}
+}
+
+func TestTimeRangeSubtract(t *testing.T) {
+	utc := func(h int) time.Time {
+		return time.Date(2024, 11, 14, h, 0, 0, 0, time.UTC)
+	}
+	r := TimeRange{Start: utc(9), End: utc(17)}
+	tests := []struct {
+		Range, Other TimeRange
+		Expect       []TimeRange
+	}{
+		{ // in the middle
+			Range:  r,
+			Other:  TimeRange{Start: utc(12), End: utc(13)},
+			Expect: []TimeRange{{Start: utc(9), End: utc(12)}, {Start: utc(13), End: utc(17)}},
+		},
+		{ // overlapping the start
+			Range:  r,
+			Other:  TimeRange{Start: utc(8), End: utc(10)},
+			Expect: []TimeRange{{Start: utc(10), End: utc(17)}},
+		},
+		{ // overlapping the end
+			Range:  r,
+			Other:  TimeRange{Start: utc(16), End: utc(18)},
+			Expect: []TimeRange{{Start: utc(9), End: utc(16)}},
+		},
+		{ // sharing the start
+			Range:  r,
+			Other:  TimeRange{Start: utc(9), End: utc(10)},
+			Expect: []TimeRange{{Start: utc(10), End: utc(17)}},
+		},
+		{ // sharing the end
+			Range:  r,
+			Other:  TimeRange{Start: utc(16), End: utc(17)},
+			Expect: []TimeRange{{Start: utc(9), End: utc(16)}},
+		},
+		{ // identical
+			Range: r,
+			Other: r,
+		},
+		{ // containing
+			Range: r,
+			Other: TimeRange{Start: utc(0), End: utc(23)},
+		},
+		{ // before
+			Range:  r,
+			Other:  TimeRange{Start: utc(6), End: utc(8)},
+			Expect: []TimeRange{r},
+		},
+		{ // adjacent before
+			Range:  r,
+			Other:  TimeRange{Start: utc(6), End: utc(9)},
+			Expect: []TimeRange{r},
+		},
+		{ // adjacent after
+			Range:  r,
+			Other:  TimeRange{Start: utc(17), End: utc(18)},
+			Expect: []TimeRange{r},
+		},
+		{ // empty other
+			Range:  r,
+			Other:  TimeRange{Start: utc(12), End: utc(12)},
+			Expect: []TimeRange{r},
+		},
+		{ // empty range
+			Range: TimeRange{Start: utc(12), End: utc(12)},
+			Other: TimeRange{Start: utc(0), End: utc(1)},
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, test.Range.Subtract(test.Other), "#%d", i)
+	}
+}
+
+func TestEachPeriod(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// over a month boundary, starting part way through a day
+	r := TimeRange{Start: time.Date(2024, 10, 30, 12, 0, 0, 0, time.UTC), End: date(2024, 11, 2)}
+	assert.Equal(t, []time.Time{date(2024, 10, 30), date(2024, 10, 31), date(2024, 11, 1)}, EachDay(r, nil))
+	assert.Equal(t, []time.Time{date(2024, 10, 28)}, EachWeek(r, nil, time.Monday))
+	assert.Equal(t, []time.Time{date(2024, 10, 1), date(2024, 11, 1)}, EachMonth(r, nil))
+
+	// across the spring-forward and fall-back transitions
+	for i, r := range []TimeRange{
+		{Start: time.Date(2024, 3, 9, 0, 0, 0, 0, nyc), End: time.Date(2024, 3, 12, 0, 0, 0, 0, nyc)},
+		{Start: time.Date(2024, 11, 2, 0, 0, 0, 0, nyc), End: time.Date(2024, 11, 5, 0, 0, 0, 0, nyc)},
+	} {
+		d := EachDay(r, nil)
+		if assert.Len(t, d, 3, "#%d", i) {
+			for j, v := range d {
+				assert.Equal(t, r.Start.AddDate(0, 0, j), v, "#%d/%d", i, j)
+				assert.Equal(t, 0, v.Hour(), "#%d/%d", i, j)
+			}
+		}
+	}
+
+	// in another location
+	r = TimeRange{Start: time.Date(2024, 11, 14, 2, 0, 0, 0, time.UTC), End: time.Date(2024, 11, 14, 6, 0, 0, 0, time.UTC)}
+	assert.Equal(t, []time.Time{time.Date(2024, 11, 13, 0, 0, 0, 0, nyc), time.Date(2024, 11, 14, 0, 0, 0, 0, nyc)}, EachDay(r, nyc))
+
+	// weeks starting on Sunday
+	r = TimeRange{Start: date(2024, 11, 14), End: date(2024, 11, 25)}
+	assert.Equal(t, []time.Time{date(2024, 11, 10), date(2024, 11, 17), date(2024, 11, 24)}, EachWeek(r, nil, time.Sunday))
+
+	// stopping early
+	var n int
+	RangeEachDay(TimeRange{Start: date(2024, 11, 1), End: date(2024, 12, 1)}, nil, func(day time.Time) bool {
+		n++
+		return n < 5
+	})
+	assert.Equal(t, 5, n)
+
+	// empty
+	assert.Nil(t, EachDay(TimeRange{Start: date(2024, 11, 1), End: date(2024, 11, 1)}, nil))
+	assert.Nil(t, EachMonth(TimeRange{Start: date(2024, 11, 2), End: date(2024, 11, 1)}, nil))
+}
+
+func TestTimeRangeMarshal(t *testing.T) {
+	r := TimeRange{
+		Start: time.Date(2024, 11, 14, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 11, 15, 12, 30, 0, 500, time.UTC),
+	}
+
+	data, err := json.Marshal(r)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"start":"2024-11-14T00:00:00Z","end":"2024-11-15T12:30:00.0000005Z"}`, string(data))
+		var v TimeRange
+		err = json.Unmarshal(data, &v)
+		if assert.NoError(t, err) {
+			assert.True(t, r.Start.Equal(v.Start))
+			assert.True(t, r.End.Equal(v.End))
+		}
+	}
+
+	text, err := r.MarshalText()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2024-11-14T00:00:00Z..2024-11-15T12:30:00.0000005Z", string(text))
+		var v TimeRange
+		err = v.UnmarshalText(text)
+		if assert.NoError(t, err) {
+			assert.True(t, r.Start.Equal(v.Start))
+			assert.True(t, r.End.Equal(v.End))
+		}
+	}
+
+	defer func(f func() time.Time) { Now = f }(Now)
+	Now = func() time.Time { return time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC) }
+	var v TimeRange
+	err = v.UnmarshalText([]byte("yesterday..now"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, TimeRange{Start: time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC), End: Now()}, v)
+	}
+	err = v.UnmarshalText([]byte("???"))
+	assert.Error(t, err)
+
+	m := map[string]TimeRange{"window": r}
+	data, err = json.Marshal(m)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"window":{"start":"2024-11-14T00:00:00Z","end":"2024-11-15T12:30:00.0000005Z"}}`, string(data))
+	}
+}
@@ -0,0 +1,86 @@
+package timeutil
+
+import (
+	"time"
+)
+
+// Schedule describes a simple recurring time, such as "every day at 09:00",
+// made up of an interval and an anchor clock time.
+type Schedule struct {
+	Interval time.Duration
+	Anchor   Clock
+}
+
+// NewSchedule creates a schedule that recurs every interval, anchored to the
+// provided clock time.
+func NewSchedule(interval time.Duration, anchor Clock) Schedule {
+	return Schedule{
+		Interval: interval,
+		Anchor:   anchor,
+	}
+}
+
+// Next returns the next occurrence of the schedule strictly after the provided
+// time, in the anchor's location, or in the location of the provided time if
+// the anchor has none.
+//
+// When the interval is a whole number of days, occurrences are stepped on the
+// calendar so they stay at the anchor's wall-clock time across daylight saving
+// transitions. Multi-day intervals are aligned to days counted from the Unix
+// epoch so that they are stable regardless of when Next is called.
+//
+// Otherwise, occurrences begin at the anchor time each day and are stepped by
+// the interval in absolute time until the anchor time on the following day.
+//
+// A schedule with a non-positive interval never recurs and Next returns the
+// zero time.
+func (s Schedule) Next(after time.Time) time.Time {
+	if s.Interval <= 0 {
+		return time.Time{}
+	}
+	loc := s.Anchor.Location
+	if loc == nil {
+		loc = after.Location()
+	}
+	date := DateOf(after.In(loc))
+
+	if s.Interval%day == 0 {
+		n := int(s.Interval / day)
+		date = date.AddDays(-mod(date.days(), n))
+		for {
+			if t := s.Anchor.OnDate(date, loc); t.After(after) {
+				return t
+			}
+			date = date.AddDays(n)
+		}
+	}
+
+	base := s.Anchor.OnDate(date, loc)
+	if base.After(after) {
+		date = date.AddDays(-1)
+		base = s.Anchor.OnDate(date, loc)
+	}
+	next := base.Add((after.Sub(base)/s.Interval + 1) * s.Interval)
+	if t := s.Anchor.OnDate(date.AddDays(1), loc); t.Before(next) {
+		return t
+	}
+	return next
+}
+
+// UntilNextClock returns the duration from now until the next occurrence of
+// the clock time, which is later today or else tomorrow, in the clock's
+// location or in now's location if the clock has none. If now is exactly the
+// clock time, the next occurrence is tomorrow's, which is usually 24 hours
+// away but may be 23 or 25 hours away across a daylight saving transition.
+func UntilNextClock(now time.Time, c Clock) time.Duration {
+	return NewSchedule(day, c).Next(now).Sub(now)
+}
+
+// mod returns the non-negative remainder of a divided by b.
+func mod(a, b int) int {
+	if r := a % b; r < 0 {
+		return r + b
+	} else {
+		return r
+	}
+}
@@ -0,0 +1,123 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("Could not load location: %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestSchedule(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	tests := []struct {
+		Schedule Schedule
+		After    time.Time
+		Expect   time.Time
+	}{
+		{ // daily, later today
+			Schedule: NewSchedule(day, Clock{Hour: 9}),
+			After:    time.Date(2024, 11, 14, 8, 0, 0, 0, time.UTC),
+			Expect:   time.Date(2024, 11, 14, 9, 0, 0, 0, time.UTC),
+		},
+		{ // daily, strictly after
+			Schedule: NewSchedule(day, Clock{Hour: 9}),
+			After:    time.Date(2024, 11, 14, 9, 0, 0, 0, time.UTC),
+			Expect:   time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{ // daily, in the anchor's location
+			Schedule: NewSchedule(day, Clock{Hour: 9, Location: nyc}),
+			After:    time.Date(2024, 11, 14, 12, 0, 0, 0, time.UTC),
+			Expect:   time.Date(2024, 11, 14, 9, 0, 0, 0, nyc),
+		},
+		{ // daily, across the spring-forward transition
+			Schedule: NewSchedule(day, Clock{Hour: 9, Location: nyc}),
+			After:    time.Date(2024, 3, 9, 10, 0, 0, 0, nyc),
+			Expect:   time.Date(2024, 3, 10, 9, 0, 0, 0, nyc),
+		},
+		{ // every other day
+			Schedule: NewSchedule(day*2, Clock{Hour: 9}),
+			After:    time.Date(2024, 11, 15, 10, 0, 0, 0, time.UTC),
+			Expect:   time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC),
+		},
+		{ // hourly
+			Schedule: NewSchedule(time.Hour, Clock{Minute: 15}),
+			After:    time.Date(2024, 11, 14, 10, 20, 0, 0, time.UTC),
+			Expect:   time.Date(2024, 11, 14, 11, 15, 0, 0, time.UTC),
+		},
+		{ // hourly, before today's anchor
+			Schedule: NewSchedule(time.Hour, Clock{Minute: 15}),
+			After:    time.Date(2024, 11, 14, 0, 5, 0, 0, time.UTC),
+			Expect:   time.Date(2024, 11, 14, 0, 15, 0, 0, time.UTC),
+		},
+		{ // hourly, across the spring-forward transition
+			Schedule: NewSchedule(time.Hour, Clock{Location: nyc}),
+			After:    time.Date(2024, 3, 10, 1, 30, 0, 0, nyc),
+			Expect:   time.Date(2024, 3, 10, 3, 0, 0, 0, nyc),
+		},
+		{ // an interval that doesn't divide the day restarts at the anchor
+			Schedule: NewSchedule(time.Hour*7, Clock{}),
+			After:    time.Date(2024, 11, 14, 22, 0, 0, 0, time.UTC),
+			Expect:   time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{ // never recurs
+			Schedule: NewSchedule(0, Clock{}),
+			After:    time.Date(2024, 11, 14, 22, 0, 0, 0, time.UTC),
+			Expect:   time.Time{},
+		},
+	}
+	for i, test := range tests {
+		v := test.Schedule.Next(test.After)
+		assert.True(t, test.Expect.Equal(v), "#%d: expected %v, got %v", i, test.Expect, v)
+	}
+}
+
+func TestUntilNextClock(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	tests := []struct {
+		Now    time.Time
+		Clock  Clock
+		Expect time.Duration
+	}{
+		{ // before the target
+			Now:    time.Date(2024, 11, 14, 1, 30, 0, 0, time.UTC),
+			Clock:  Clock{Hour: 3},
+			Expect: time.Minute * 90,
+		},
+		{ // after the target
+			Now:    time.Date(2024, 11, 14, 4, 0, 0, 0, time.UTC),
+			Clock:  Clock{Hour: 3},
+			Expect: time.Hour * 23,
+		},
+		{ // exactly the target
+			Now:    time.Date(2024, 11, 14, 3, 0, 0, 0, time.UTC),
+			Clock:  Clock{Hour: 3},
+			Expect: time.Hour * 24,
+		},
+		{ // in the clock's location
+			Now:    time.Date(2024, 11, 14, 7, 0, 0, 0, time.UTC),
+			Clock:  Clock{Hour: 3, Location: nyc},
+			Expect: time.Hour,
+		},
+		{ // across the spring-forward transition
+			Now:    time.Date(2024, 3, 9, 3, 0, 0, 0, nyc),
+			Clock:  Clock{Hour: 3},
+			Expect: time.Hour * 23,
+		},
+		{ // across the fall-back transition
+			Now:    time.Date(2024, 11, 2, 3, 0, 0, 0, nyc),
+			Clock:  Clock{Hour: 3},
+			Expect: time.Hour * 25,
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.Expect, UntilNextClock(test.Now, test.Clock), "#%d", i)
+	}
+}
@@ -0,0 +1,103 @@
+package timeutil
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// PercentileDuration returns the p-th percentile of a set of durations, where
+// p is between 0 and 100, interpolating linearly between the closest ranks
+// when it falls between two values. Values of p outside that range are
+// clamped. The input is not modified. If the input is empty, the result is
+// zero.
+func PercentileDuration(ds []time.Duration, p float64) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	s := make([]time.Duration, len(ds))
+	copy(s, ds)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+
+	p = math.Max(0, math.Min(100, p))
+	r := p / 100 * float64(len(s)-1)
+	i := int(r)
+	if i >= len(s)-1 {
+		return s[len(s)-1]
+	}
+	f := r - float64(i)
+	// the difference is computed as an unsigned value so that it does not
+	// overflow when the values are far apart, and the offset is clamped to it
+	// so that the result always falls between them
+	n := uint64(s[i+1]) - uint64(s[i])
+	o := uint64(math.Round(f * float64(n)))
+	if o > n {
+		o = n
+	}
+	return time.Duration(uint64(s[i]) + o)
+}
+
+// MedianDuration returns the median of a set of durations, which is the
+// mean of the two middle values when there is an even number of them. The
+// input is not modified. If the input is empty, the result is zero.
+func MedianDuration(ds []time.Duration) time.Duration {
+	return PercentileDuration(ds, 50)
+}
+
+// MeanDuration returns the arithmetic mean of a set of durations, truncated
+// to the nanosecond. It does not overflow even if the sum of the durations
+// would. If the input is empty, the result is zero.
+func MeanDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	// the mean is accumulated as a quotient and a remainder, carrying into the
+	// quotient whenever the remainder reaches n so that it never overflows
+	n := time.Duration(len(ds))
+	var q, r time.Duration
+	for _, d := range ds {
+		q += d / n
+		r += d % n
+		if r >= n {
+			q, r = q+1, r-n
+		} else if r <= -n {
+			q, r = q-1, r+n
+		}
+	}
+	// the mean is q + r/n where |r| < n, which truncates toward zero to q
+	// unless the remainder has the opposite sign
+	switch {
+	case q > 0 && r < 0:
+		return q - 1
+	case q < 0 && r > 0:
+		return q + 1
+	default:
+		return q
+	}
+}
+
+// SumDuration returns the sum of a set of durations. If the sum would overflow,
+// it is saturated to the largest or smallest representable duration,
+// depending on its sign, and ok is false, like [MulDuration]. Intermediate
+// overflow which is cancelled out by later values does not affect the result.
+// If the input is empty, the result is zero.
+func SumDuration(ds []time.Duration) (v time.Duration, ok bool) {
+	var c int // the number of times the running sum has wrapped around
+	for _, d := range ds {
+		s := v + d
+		if d > 0 && s < v {
+			c++
+		} else if d < 0 && s > v {
+			c--
+		}
+		v = s
+	}
+	switch {
+	case c > 0:
+		return maxDuration, false
+	case c < 0:
+		return minDuration, false
+	default:
+		return v, true
+	}
+}
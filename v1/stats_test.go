@@ -0,0 +1,79 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentileDuration(t *testing.T) {
+	var ds []time.Duration
+	for i := 100; i > 0; i-- { // 100ms, 99ms, ... 1ms
+		ds = append(ds, time.Millisecond*time.Duration(i))
+	}
+	assert.Equal(t, time.Millisecond, PercentileDuration(ds, 0))
+	assert.Equal(t, time.Microsecond*50500, PercentileDuration(ds, 50))
+	assert.Equal(t, time.Microsecond*90100, PercentileDuration(ds, 90))
+	assert.Equal(t, time.Microsecond*99010, PercentileDuration(ds, 99))
+	assert.Equal(t, time.Millisecond*100, PercentileDuration(ds, 100))
+	assert.Equal(t, time.Millisecond*100, PercentileDuration(ds, 150))
+	assert.Equal(t, time.Millisecond, PercentileDuration(ds, -5))
+	assert.Equal(t, time.Millisecond*100, ds[0], "Expected the input not to be modified")
+
+	assert.Equal(t, time.Duration(0), PercentileDuration(nil, 50))
+	assert.Equal(t, time.Second, PercentileDuration([]time.Duration{time.Second}, 99))
+
+	extreme := []time.Duration{-5e18, 5e18}
+	assert.Equal(t, time.Duration(0), PercentileDuration(extreme, 50))
+	assert.Equal(t, time.Duration(-5e18), PercentileDuration(extreme, 0))
+	assert.Equal(t, time.Duration(5e18), PercentileDuration(extreme, 100))
+	assert.Equal(t, time.Duration(4e18), PercentileDuration(extreme, 90))
+	assert.Equal(t, maxDuration, PercentileDuration([]time.Duration{minDuration, maxDuration}, 100))
+	assert.Equal(t, time.Duration(0), PercentileDuration([]time.Duration{minDuration, maxDuration}, 50))
+}
+
+func TestMedianDuration(t *testing.T) {
+	assert.Equal(t, time.Second*2, MedianDuration([]time.Duration{time.Second * 3, time.Second, time.Second * 2}))
+	assert.Equal(t, time.Millisecond*2500, MedianDuration([]time.Duration{time.Second * 4, time.Second, time.Second * 3, time.Second * 2}))
+	assert.Equal(t, time.Duration(0), MedianDuration(nil))
+	assert.Equal(t, time.Duration(0), MedianDuration([]time.Duration{5e18, -5e18}))
+}
+
+func TestMeanDuration(t *testing.T) {
+	assert.Equal(t, time.Second*2, MeanDuration([]time.Duration{time.Second, time.Second * 2, time.Second * 3}))
+	assert.Equal(t, time.Duration(1), MeanDuration([]time.Duration{1, 1, 2}))
+	assert.Equal(t, maxDuration-1, MeanDuration([]time.Duration{maxDuration, maxDuration - 2}))
+	assert.Equal(t, minDuration+1, MeanDuration([]time.Duration{minDuration, minDuration + 2}))
+	assert.Equal(t, time.Duration(0), MeanDuration([]time.Duration{-1, 2}))
+	assert.Equal(t, time.Duration(0), MeanDuration([]time.Duration{-1, -1, 3}))
+	assert.Equal(t, time.Duration(0), MeanDuration([]time.Duration{1, 1, -3}))
+	assert.Equal(t, time.Duration(-1), MeanDuration([]time.Duration{-3, -1, 1}))
+	assert.Equal(t, time.Duration(2), MeanDuration([]time.Duration{-5, 6, 7}))
+	assert.Equal(t, time.Duration(-2), MeanDuration([]time.Duration{5, -6, -7}))
+	assert.Equal(t, time.Duration(0), MeanDuration([]time.Duration{maxDuration, minDuration}))
+	assert.Equal(t, time.Duration(-1), MeanDuration([]time.Duration{maxDuration, minDuration, minDuration, maxDuration, -4}))
+	assert.Equal(t, time.Duration(0), MeanDuration(nil))
+}
+
+func TestSumDuration(t *testing.T) {
+	tests := []struct {
+		Durations []time.Duration
+		Expect    time.Duration
+		OK        bool
+	}{
+		{nil, 0, true},
+		{[]time.Duration{time.Hour, time.Minute * 30, time.Second}, time.Hour + time.Minute*30 + time.Second, true},
+		{[]time.Duration{time.Hour, -time.Hour * 3}, -time.Hour * 2, true},
+		{[]time.Duration{maxDuration, 1}, maxDuration, false},
+		{[]time.Duration{minDuration, -1}, minDuration, false},
+		{[]time.Duration{maxDuration, maxDuration, minDuration}, maxDuration - 1, true},
+		{[]time.Duration{minDuration, minDuration, maxDuration, maxDuration}, -2, true},
+		{[]time.Duration{maxDuration, maxDuration, maxDuration, minDuration}, maxDuration, false},
+	}
+	for i, test := range tests {
+		v, ok := SumDuration(test.Durations)
+		assert.Equal(t, test.Expect, v, "#%d", i)
+		assert.Equal(t, test.OK, ok, "#%d", i)
+	}
+}
@@ -0,0 +1,86 @@
+package timeutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// StructuredDuration is a duration which is marshaled to JSON as an object
+// made up of a count and a unit, like {"value": 90, "unit": "m"}, for
+// interoperability with APIs which expect that form. Otherwise it is the
+// same as [Duration].
+type StructuredDuration time.Duration
+
+type structuredDurationJSON struct {
+	Value json.Number `json:"value"`
+	Unit  string      `json:"unit"`
+}
+
+// structuredUnits are the units a structured duration is marshaled in, from
+// largest to smallest.
+var structuredUnits = []time.Duration{
+	week,
+	day,
+	time.Hour,
+	time.Minute,
+	time.Second,
+	time.Millisecond,
+	time.Microsecond,
+	time.Nanosecond,
+}
+
+// MarshalJSON encodes the duration as an integer count of the largest unit
+// which represents it exactly. The microsecond unit is encoded as "us". A
+// zero duration is encoded as zero seconds.
+func (d StructuredDuration) MarshalJSON() ([]byte, error) {
+	v := time.Duration(d)
+	u := time.Second
+	if v != 0 {
+		for _, e := range structuredUnits {
+			if v%e == 0 {
+				u = e
+				break
+			}
+		}
+	}
+	name := unitNames[u]
+	if u == time.Microsecond {
+		name = "us"
+	}
+	return json.Marshal(structuredDurationJSON{Value: json.Number(fmt.Sprint(int64(v / u))), Unit: name})
+}
+
+// UnmarshalJSON decodes the duration from an object with a count, which may
+// have a fraction, and a unit, as accepted by [ParseDuration].
+func (d *StructuredDuration) UnmarshalJSON(data []byte) error {
+	var v structuredDurationJSON
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	unit, ok := unitMap[v.Unit]
+	if !ok {
+		return errors.New("time: unknown unit " + quote(v.Unit) + " in structured duration")
+	}
+	if n, err := v.Value.Int64(); err == nil {
+		r, ok := MulDuration(time.Duration(unit), n)
+		if !ok {
+			return fmt.Errorf("%w in structured duration %s", ErrDurationOverflow, data)
+		}
+		*d = StructuredDuration(r)
+		return nil
+	}
+	f, err := v.Value.Float64()
+	if err != nil {
+		return errors.New("time: invalid value " + quote(v.Value.String()) + " in structured duration")
+	}
+	ns := math.Round(f * float64(unit))
+	if ns >= float64(maxDuration) || ns < float64(minDuration) {
+		return fmt.Errorf("%w in structured duration %s", ErrDurationOverflow, data)
+	}
+	*d = StructuredDuration(ns)
+	return nil
+}
@@ -0,0 +1,56 @@
+package timeutil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuredDuration(t *testing.T) {
+	tests := []struct {
+		Duration time.Duration
+		Expect   string
+	}{
+		{0, `{"value":0,"unit":"s"}`},
+		{time.Minute * 90, `{"value":90,"unit":"m"}`},
+		{time.Hour * 2, `{"value":2,"unit":"h"}`},
+		{day * 3, `{"value":3,"unit":"d"}`},
+		{week * 2, `{"value":2,"unit":"w"}`},
+		{time.Millisecond * 1500, `{"value":1500,"unit":"ms"}`},
+		{time.Microsecond * 250, `{"value":250,"unit":"us"}`},
+		{time.Second + time.Nanosecond, `{"value":1000000001,"unit":"ns"}`},
+		{-time.Second * 30, `{"value":-30,"unit":"s"}`},
+		{maxDuration, `{"value":9223372036854775807,"unit":"ns"}`},
+	}
+	for i, test := range tests {
+		data, err := json.Marshal(StructuredDuration(test.Duration))
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Expect, string(data), "#%d", i)
+		}
+		var v StructuredDuration
+		err = json.Unmarshal([]byte(test.Expect), &v)
+		if assert.NoError(t, err, "#%d", i) {
+			assert.Equal(t, test.Duration, time.Duration(v), "#%d", i)
+		}
+	}
+
+	var v StructuredDuration
+	err := json.Unmarshal([]byte(`{"value":1.5,"unit":"h"}`), &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Minute*90, time.Duration(v))
+	}
+	err = json.Unmarshal([]byte(`{"value":250,"unit":"µs"}`), &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Microsecond*250, time.Duration(v))
+	}
+	err = json.Unmarshal([]byte(`{"value":1,"unit":"fortnight"}`), &v)
+	assert.Error(t, err)
+	err = json.Unmarshal([]byte(`{"value":"x","unit":"s"}`), &v)
+	assert.Error(t, err)
+	err = json.Unmarshal([]byte(`{"value":9999999999999,"unit":"h"}`), &v)
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+	err = json.Unmarshal([]byte(`{"value":1e30,"unit":"s"}`), &v)
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+}
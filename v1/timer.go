@@ -0,0 +1,94 @@
+package timeutil
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timer measures the time taken by a named operation and, optionally, by the
+// operations nested within it, for reporting as a timing tree like:
+//
+//	build took 3s
+//	  compile took 2s
+//	  link took 1s
+//
+// Timers obtain the current time from [Now]. They are safe to use
+// concurrently, so children may be started and stopped from other goroutines.
+type Timer struct {
+	name     string
+	start    time.Time
+	mu       sync.Mutex
+	stop     time.Time
+	children []*Timer
+}
+
+// NewTimer starts a timer with the provided name.
+func NewTimer(name string) *Timer {
+	return &Timer{name: name, start: Now()}
+}
+
+// Child starts a timer nested within this one, which is rendered beneath it.
+func (t *Timer) Child(name string) *Timer {
+	c := NewTimer(name)
+	t.mu.Lock()
+	t.children = append(t.children, c)
+	t.mu.Unlock()
+	return c
+}
+
+// Stop stops the timer, if it is not already stopped, and returns the time it
+// measured. Stopping a timer does not stop its children.
+func (t *Timer) Stop() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stop.IsZero() {
+		t.stop = Now()
+	}
+	return t.stop.Sub(t.start)
+}
+
+// Elapsed returns the time measured by the timer, or the time elapsed so far
+// if it has not been stopped.
+func (t *Timer) Elapsed() time.Duration {
+	d, _ := t.elapsed()
+	return d
+}
+
+func (t *Timer) elapsed() (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stop.IsZero() {
+		return Now().Sub(t.start), false
+	}
+	return t.stop.Sub(t.start), true
+}
+
+// String renders the timer and its children as a tree, one line per timer in
+// the form "name took 1s", with children indented by two spaces beneath their
+// parent in the order they were started. Durations are formatted by
+// [FormatSimplifiedDuration]. A timer which has not been stopped reports the
+// time elapsed so far, marked as "(running)".
+func (t *Timer) String() string {
+	var b strings.Builder
+	t.render(&b, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (t *Timer) render(b *strings.Builder, depth int) {
+	d, stopped := t.elapsed()
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(t.name)
+	b.WriteString(" took ")
+	b.WriteString(FormatSimplifiedDuration(d))
+	if !stopped {
+		b.WriteString(" (running)")
+	}
+	b.WriteByte('\n')
+	t.mu.Lock()
+	children := t.children
+	t.mu.Unlock()
+	for _, c := range children {
+		c.render(b, depth+1)
+	}
+}
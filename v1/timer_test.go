@@ -0,0 +1,40 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimer(t *testing.T) {
+	defer func(f func() time.Time) { Now = f }(Now)
+	now := time.Date(2024, 11, 14, 18, 17, 0, 0, time.UTC)
+	Now = func() time.Time { return now }
+	advance := func(d time.Duration) { now = now.Add(d) }
+
+	build := NewTimer("build")
+	compile := build.Child("compile")
+	parse := compile.Child("parse")
+	advance(time.Second * 2)
+	assert.Equal(t, time.Second*2, parse.Stop())
+	advance(time.Second * 3)
+	assert.Equal(t, time.Second*5, compile.Stop())
+	link := build.Child("link")
+	advance(time.Millisecond * 1500)
+	link.Stop()
+	assert.Equal(t, "build took 6s (running)\n  compile took 5s\n    parse took 2s\n  link took 1s", build.String())
+
+	advance(time.Minute * 2)
+	assert.Equal(t, time.Minute*2+time.Millisecond*6500, build.Stop())
+	advance(time.Hour)
+	assert.Equal(t, time.Minute*2+time.Millisecond*6500, build.Stop())
+	assert.Equal(t, time.Minute*2+time.Millisecond*6500, build.Elapsed())
+	assert.Equal(t, "build took 2m\n  compile took 5s\n    parse took 2s\n  link took 1s", build.String())
+
+	single := NewTimer("query")
+	advance(time.Millisecond * 250)
+	assert.Equal(t, time.Millisecond*250, single.Elapsed())
+	single.Stop()
+	assert.Equal(t, "query took 250ms", single.String())
+}